@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// ClientFilesCommand writes the files a client reads at startup, such as the
+// validator's config holding its key, and removes them once they were read.
+// systemd units run it around the client, see `service install`.
+var ClientFilesCommand = &cobra.Command{
+	Use:    process.ClientFilesCommand,
+	Short:  "Write and remove the files a client reads at startup",
+	Hidden: true,
+}
+
+var clientFilesWriteCommand = &cobra.Command{
+	Use:   "write <client>",
+	Short: "Write a client's files",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files, err := clientFiles(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		return process.WriteClientFiles(files)
+	},
+}
+
+var clientFilesCleanupCommand = &cobra.Command{
+	Use:   "cleanup <client>",
+	Short: "Remove a client's files once it has read them or exited",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pid, _ := cmd.Flags().GetInt("pid")
+		if pid <= 0 {
+			return fmt.Errorf("--pid is required")
+		}
+		files, err := clientFiles(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		process.AwaitClientFiles(files, pid)
+		return nil
+	},
+}
+
+// clientFiles returns the files of a configured client, moved to --dir
+func clientFiles(cmd *cobra.Command, name string) ([]types.ClientFile, error) {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		return nil, fmt.Errorf("--dir is required")
+	}
+	if !options.LoadedConfig {
+		return nil, fmt.Errorf("no config found")
+	}
+	client, err := clients.NewClient(options.Config, types.ClientType(name))
+	if err != nil {
+		return nil, err
+	}
+	files := client.Spec().Files
+	for i := range files {
+		files[i].Path = filepath.Join(dir, filepath.Base(files[i].Path))
+	}
+	return files, nil
+}
+
+func init() {
+	ClientFilesCommand.PersistentFlags().String("dir", "", "Directory the files are written to")
+	clientFilesCleanupCommand.Flags().Int("pid", 0, "PID of the client")
+	ClientFilesCommand.AddCommand(clientFilesWriteCommand, clientFilesCleanupCommand)
+}
@@ -17,7 +17,7 @@ import (
 var (
 	defaultConsensusClientSettings = types.ClientConfig{
 		Name: types.ClientPrysm,
-		Port: []int{5052, 9000},
+		Port: []int{9000, 9001},
 	}
 	defaultExecutionClientSettings = types.ClientConfig{
 		Name:          types.ClientGeth,
@@ -27,7 +27,7 @@ var (
 	defaultJunoConfig = types.JunoConfig{
 		Port:    6060,
 		EthNode: "wss://eth.drpc.org",
-		Environment: []string{
+		Env: []string{
 			"JUNO_HTTP_PORT=6060",
 			"JUNO_HTTP_HOST=0.0.0.0",
 		},
@@ -54,7 +54,7 @@ func handleValidatorWalletSetup(network string) (*types.WalletConfig, error) {
 	}
 
 	fmt.Println(utils.Cyan("🚀 Deploying new wallet for validator..."))
-	_, err := utils.DeployAccount(network)
+	deployed, err := utils.DeployAccount(network, "default")
 	if err != nil {
 		return nil, fmt.Errorf("error deploying account: %w", err)
 	}
@@ -87,13 +87,13 @@ func handleValidatorWalletSetup(network string) (*types.WalletConfig, error) {
 		RewardAddress:  rewardAddr,
 		StakeCommision: fmt.Sprintf("%d", stakeCommission),
 		Wallet: types.Wallet{
-			Address:    "${STARKNET_WALLET}",
-			ClassHash:  "${STARKNET_CLASS_HASH}",
-			Deployed:   true,
-			Legacy:     false,
-			PrivateKey: "${STARKNET_PRIVATE_KEY}",
-			PublicKey:  "${STARKNET_PUBLIC_KEY}",
-			Salt:       "${STARKNET_SALT}",
+			Address:   "${STARKNET_WALLET}",
+			ClassHash: "${STARKNET_CLASS_HASH}",
+			Deployed:  true,
+			Legacy:    false,
+			PublicKey: "${STARKNET_PUBLIC_KEY}",
+			Salt:      "${STARKNET_SALT}",
+			Keystore:  deployed.Keystore,
 		},
 	}
 	return walletConfig, nil
@@ -136,24 +136,14 @@ func generateConfig(network string, starknetNode bool, validator bool, walletCon
 	}
 
 	if validator {
+		config.ValidatorConfig.SignerConfig.OperationalAddress = "${STARKNET_WALLET}"
 		if walletConfig != nil {
 			config.Wallet = *walletConfig
+			config.ValidatorConfig.SignerConfig.Keystore = walletConfig.Wallet.Keystore
 		}
-		config.ValidatorConfig = types.ValidatorConfig{
-			ProviderConfig: struct {
-				JunoRPC string `json:"http" yaml:"juno_rpc_http"`
-				JunoWS  string `json:"ws" yaml:"juno_rpc_ws"`
-			}{
-				JunoRPC: "http://localhost:6060",
-				JunoWS:  "ws://localhost:6060",
-			},
-			SignerConfig: struct {
-				OperationalAddress string `json:"operational_address"`
-				WalletPrivateKey   string `json:"privateKey"`
-			}{
-				OperationalAddress: "${STARKNET_WALLET}",
-				WalletPrivateKey:   "${STARKNET_PRIVATE_KEY}",
-			},
+		if config.ValidatorConfig.SignerConfig.Keystore == "" {
+			// Wallets created before keystores keep their key in .starknode.env
+			config.ValidatorConfig.SignerConfig.WalletPrivateKey = "${STARKNET_PRIVATE_KEY}"
 		}
 	}
 
@@ -2,6 +2,8 @@ package configcommand
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/thebuidl-grid/starknode-kit/cli/options"
@@ -81,6 +83,7 @@ func showConfig(part string) {
 		utils.PrintKV("Client", options.Config.ExecutionCientSettings.Name)
 		utils.PrintKV("Type", options.Config.ExecutionCientSettings.ExecutionType)
 		utils.PrintKV("Ports", options.Config.ExecutionCientSettings.Port)
+		printExtraSettings(options.Config.ExecutionCientSettings.ExtraArgs, options.Config.ExecutionCientSettings.Env)
 	}
 
 	if part == "all" || part == "cl" {
@@ -90,13 +93,14 @@ func showConfig(part string) {
 		utils.PrintKV("Client", options.Config.ConsensusCientSettings.Name)
 		utils.PrintKV("Ports", options.Config.ConsensusCientSettings.Port)
 		utils.PrintKV("Checkpoint", options.Config.ConsensusCientSettings.ConsensusCheckpoint)
+		printExtraSettings(options.Config.ConsensusCientSettings.ExtraArgs, options.Config.ConsensusCientSettings.Env)
 	}
 
 	if part == "all" && options.Config.IsValidatorNode {
 		utils.PrintSection("Juno Node")
 		utils.PrintKV("Port", options.Config.JunoConfig.Port)
 		utils.PrintKV("Eth Node", options.Config.JunoConfig.EthNode)
-		utils.PrintKV("Environment", options.Config.JunoConfig.Environment)
+		printExtraSettings(options.Config.JunoConfig.ExtraArgs, slices.Concat(options.Config.JunoConfig.Environment, options.Config.JunoConfig.Env))
 
 		utils.PrintSection("Wallet")
 		utils.PrintKV("Name", options.Config.Wallet.Name)
@@ -104,6 +108,16 @@ func showConfig(part string) {
 	}
 }
 
+// printExtraSettings shows user supplied flags and environment, if any
+func printExtraSettings(extraArgs, env []string) {
+	if len(extraArgs) > 0 {
+		utils.PrintKV("Extra args", strings.Join(extraArgs, " "))
+	}
+	if len(env) > 0 {
+		utils.PrintKV("Env", strings.Join(env, ", "))
+	}
+}
+
 func setNetwork(network string) {
 	if !options.LoadedConfig {
 		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
@@ -121,6 +135,7 @@ func setNetwork(network string) {
 	}
 
 	fmt.Printf("%s\n", utils.Green(fmt.Sprintf("Network set to %s", network)))
+	printServiceHint()
 }
 
 func init() {
@@ -2,11 +2,15 @@ package configcommand
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/service"
 	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 
@@ -50,12 +54,31 @@ func runSetCommand(target string, args []string) {
 		fmt.Println(utils.Red(fmt.Sprintf("❌ Error processing config arguments: %v", err)))
 		return
 	}
+	if err := clients.ValidatePorts(options.Config); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
 
 	if err := utils.UpdateStarkNodeConfig(options.Config); err != nil {
 		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to save config: %v", err)))
 	}
 
 	fmt.Println(utils.Green("✅ Configuration updated successfully!"))
+	printServiceHint()
+}
+
+// printServiceHint reminds the user to regenerate systemd units that were
+// generated from the previous configuration
+func printServiceHint() {
+	for _, userScope := range []bool{false, true} {
+		if units, err := service.InstalledUnits(userScope); err == nil && len(units) > 0 {
+			flag := ""
+			if userScope {
+				flag = " --user"
+			}
+			fmt.Println(utils.Yellow(fmt.Sprintf("💡 Run `starknode-kit service install%s` to regenerate your systemd units.", flag)))
+		}
+	}
 }
 
 func processConfigArgs(cfg *t.StarkNodeKitConfig, args []string, target string) error {
@@ -83,18 +106,20 @@ func applyConfigUpdate(cfg *t.StarkNodeKitConfig, key, value, target string) err
 
 	switch target {
 	case "execution":
-		_, err := utils.GetExecutionClient(value)
-		if err != nil {
-			return fmt.Errorf(`%w\nSupported execution clients are:\n  - geth\n  - reth`, err)
+		if key == "client" {
+			if _, err := utils.GetExecutionClient(value); err != nil {
+				return fmt.Errorf(`%w\nSupported execution clients are:\n  - geth\n  - reth`, err)
+			}
 		}
 		updated, err = setClientConfigValue(cfg.ExecutionCientSettings, key, value)
 		if err == nil {
 			cfg.ExecutionCientSettings = updated.(t.ClientConfig)
 		}
 	case "consensus":
-		_, err := utils.GetConsensusClient(value)
-		if err != nil {
-			return fmt.Errorf(`%w\nSupported execution clients are:\n  - geth\n  - reth`, err)
+		if key == "client" {
+			if _, err := utils.GetConsensusClient(value); err != nil {
+				return fmt.Errorf(`%w\nSupported consensus clients are:\n  - lighthouse\n  - prysm`, err)
+			}
 		}
 		updated, err = setClientConfigValue(cfg.ConsensusCientSettings, key, value)
 		if err == nil {
@@ -122,13 +147,43 @@ func applyConfigUpdate(cfg *t.StarkNodeKitConfig, key, value, target string) err
 func setClientConfigValue[T t.ClientConfig | t.JunoConfig](clientCfg T, key, value string) (T, error) {
 	switch c := any(clientCfg).(type) {
 	case t.JunoConfig:
-		if key != "eth_node" {
-			return clientCfg, fmt.Errorf("invalid key '%s' for starknet config: only 'eth_node' is accepted", key)
-		}
-		if _, err := url.ParseRequestURI(value); err != nil {
-			return clientCfg, fmt.Errorf("invalid URL format for eth_node: '%s'", value)
+		switch key {
+		case "eth_node":
+			if _, err := url.ParseRequestURI(value); err != nil {
+				return clientCfg, fmt.Errorf("invalid URL format for eth_node: '%s'", value)
+			}
+			c.EthNode = value
+		case "stop_timeout":
+			timeout, err := parseStopTimeout(value)
+			if err != nil {
+				return clientCfg, err
+			}
+			c.StopTimeout = timeout
+		case "extra_args":
+			c.ExtraArgs = strings.Fields(value)
+		case "env":
+			env, err := parseEnv(value)
+			if err != nil {
+				return clientCfg, err
+			}
+			c.Env = env
+		case "http_port":
+			port, err := parsePort(key, value)
+			if err != nil {
+				return clientCfg, err
+			}
+			// Port takes precedence over listen.http, keep them in sync
+			c.Port = port
+			c.Listen.HTTP = port
+		default:
+			if ok, err := setListenValue(&c.Listen, key, value); ok {
+				if err != nil {
+					return clientCfg, err
+				}
+				return any(c).(T), nil
+			}
+			return clientCfg, fmt.Errorf("invalid key '%s' for starknet config: only 'eth_node', 'stop_timeout', 'extra_args', 'env', 'http_port', 'ws_port' and 'bind_address' are accepted", key)
 		}
-		c.EthNode = value
 		return any(c).(T), nil
 	case t.ClientConfig:
 		switch key {
@@ -142,12 +197,41 @@ func setClientConfigValue[T t.ClientConfig | t.JunoConfig](clientCfg T, key, val
 			c.Port = ports
 		case "type":
 			c.ExecutionType = value
+		case "stop_timeout":
+			timeout, err := parseStopTimeout(value)
+			if err != nil {
+				return clientCfg, err
+			}
+			c.StopTimeout = timeout
+		case "extra_args":
+			c.ExtraArgs = strings.Fields(value)
+		case "env":
+			env, err := parseEnv(value)
+			if err != nil {
+				return clientCfg, err
+			}
+			c.Env = env
 		default:
+			if ok, err := setListenValue(&c.Listen, key, value); ok {
+				if err != nil {
+					return clientCfg, err
+				}
+				return any(c).(T), nil
+			}
 			return clientCfg, fmt.Errorf(`
 "unknown config key: %s", key
 Available keys you can set:
   - client           (client name)
-  - port             (client ports, comma-separated)`, key)
+  - port             (client ports, comma-separated)
+  - stop_timeout     (time to wait for a graceful shutdown, e.g. 5m)
+  - extra_args       (space-separated flags, override generated flags of the same name)
+  - env              (comma-separated KEY=VALUE pairs)
+  - http_port        (HTTP RPC or beacon API port)
+  - auth_rpc_port    (engine API port, execution clients)
+  - grpc_port        (gRPC port, prysm)
+  - metrics_port     (metrics port)
+  - bind_address     (address the RPC listeners bind to)
+  - metrics_address  (address the metrics listener binds to)`, key)
 		}
 		return any(c).(T), nil
 	default:
@@ -171,3 +255,73 @@ func parsePorts(value string) ([]int, error) {
 	}
 	return ports, nil
 }
+
+// setListenValue applies a listener key to ports. It reports false if key is
+// not a listener setting.
+func setListenValue(ports *t.PortsConfig, key, value string) (bool, error) {
+	var (
+		target *int
+		err    error
+	)
+	switch key {
+	case "http_port":
+		target = &ports.HTTP
+	case "ws_port":
+		target = &ports.WS
+	case "auth_rpc_port":
+		target = &ports.AuthRPC
+	case "grpc_port":
+		target = &ports.GRPC
+	case "metrics_port":
+		target = &ports.Metrics
+	case "bind_address":
+		ports.BindAddress, err = parseBindAddress(key, value)
+		return true, err
+	case "metrics_address":
+		ports.MetricsAddress, err = parseBindAddress(key, value)
+		return true, err
+	default:
+		return false, nil
+	}
+	*target, err = parsePort(key, value)
+	return true, err
+}
+
+func parsePort(key, value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid %s '%s': must be a number between 1 and 65535", key, value)
+	}
+	return port, nil
+}
+
+func parseBindAddress(key, value string) (string, error) {
+	if net.ParseIP(value) == nil && value != "localhost" {
+		return "", fmt.Errorf("invalid %s '%s': must be an IP address such as 127.0.0.1 or 0.0.0.0", key, value)
+	}
+	return value, nil
+}
+
+func parseStopTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid stop_timeout '%s': must be a positive duration such as 90s or 5m", value)
+	}
+	return timeout, nil
+}
+
+// parseEnv parses comma-separated KEY=VALUE pairs. An empty value clears the list.
+func parseEnv(value string) ([]string, error) {
+	var env []string
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		if key, _, ok := strings.Cut(pair, "="); !ok || key == "" {
+			return nil, fmt.Errorf("invalid env entry '%s': must be KEY=VALUE", pair)
+		}
+		env = append(env, pair)
+	}
+	return env, nil
+}
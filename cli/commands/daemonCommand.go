@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
+	"github.com/thebuidl-grid/starknode-kit/pkg/supervisor"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+var DaemonCommand = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the local management daemon",
+	Long: `Runs a long-lived daemon that owns the lifecycle of the clients it starts
+and serves a control API on a Unix socket in the state directory.
+
+Clients started through the daemon are supervised and restarted if they crash.
+While the daemon is running, 'run', 'stop', 'status' and 'monitor' go through
+it; without it they manage the clients directly.
+
+API (HTTP over the socket):
+  GET  /status                   state of every client
+  GET  /sync                     sync progress of the configured clients
+  POST /clients/{name}/start     start and supervise a client
+  POST /clients/{name}/stop      stop a client
+  POST /clients/{name}/restart   restart a client with the current config
+  GET  /clients/{name}/logs      last log lines (?lines=N)
+
+Stopping the daemon with Ctrl+C or SIGTERM stops the clients it manages in
+reverse dependency order.`,
+	Run: daemonCommand,
+}
+
+func daemonCommand(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ No config found."))
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit config new` to create a config file."))
+		return
+	}
+
+	opts := supervisor.DefaultOptions()
+	opts.InitialBackoff, _ = cmd.Flags().GetDuration("backoff")
+	opts.MaxBackoff, _ = cmd.Flags().GetDuration("max-backoff")
+	opts.CrashLoopLimit, _ = cmd.Flags().GetInt("crash-loop-limit")
+	startAll, _ := cmd.Flags().GetBool("start-all")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := daemon.NewServer(opts).Serve(ctx, startAll); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		os.Exit(1)
+	}
+	fmt.Println(utils.Green("✅ Daemon stopped."))
+}
+
+func init() {
+	defaults := supervisor.DefaultOptions()
+	DaemonCommand.Flags().Bool("start-all", false, "Start every configured client in dependency order")
+	DaemonCommand.Flags().Duration("backoff", defaults.InitialBackoff, "Delay before the first restart of a crashed client")
+	DaemonCommand.Flags().Duration("max-backoff", defaults.MaxBackoff, "Maximum delay between restarts")
+	DaemonCommand.Flags().Int("crash-loop-limit", defaults.CrashLoopLimit, "Exits within the crash loop window before a client is given up on")
+}
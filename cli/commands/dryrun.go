@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// addDryRunFlags adds the flags that print launch plans instead of starting clients
+func addDryRunFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "Print the resolved command, environment and paths of each client without starting it")
+	cmd.Flags().Bool("json", false, "With --dry-run, print the launch plans as JSON")
+}
+
+// printDryRun prints how each client would be launched, secrets redacted
+func printDryRun(cmd *cobra.Command, launch []types.IClient) {
+	plans := make([]clients.LaunchPlan, 0, len(launch))
+	for _, c := range launch {
+		plans = append(plans, clients.Plan(c))
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(plans); err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		}
+		return
+	}
+
+	fmt.Println(utils.Yellow("👀 Dry run, nothing will be started."))
+	for _, plan := range plans {
+		utils.PrintSection(plan.Client)
+		utils.PrintKV("Command", shellJoin(append([]string{plan.Command}, plan.Args...)))
+		env := "(inherited only)"
+		if len(plan.Env) > 0 {
+			env = shellJoin(plan.Env)
+		}
+		utils.PrintKV("Environment", env)
+		utils.PrintKV("Working dir", plan.WorkDir)
+		utils.PrintKV("Log file", plan.LogFile)
+		utils.PrintKV("Data dir", plan.DataDir)
+		for _, file := range plan.Files {
+			utils.PrintKV("Writes", file+" (mode 0600, removed once read)")
+		}
+	}
+}
+
+// shellJoin quotes words so the result can be pasted into a shell
+func shellJoin(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		if word != "" && !strings.ContainsAny(word, " \t\n'\"\\$`*?[]{}()<>|&;#~!") {
+			quoted[i] = word
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
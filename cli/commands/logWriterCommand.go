@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/pkg/logrotate"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+)
+
+// LogWriterCommand copies a detached client's output from stdin into its
+// rotating log files. It is started by `start`/`run` and exits with the client.
+var LogWriterCommand = &cobra.Command{
+	Use:    process.LogWriterCommand,
+	Short:  "Write a client's output to rotating log files",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   logWriterCommand,
+}
+
+func logWriterCommand(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	name, _ := cmd.Flags().GetString("name")
+	if dir == "" || name == "" {
+		return fmt.Errorf("--dir and --name are required")
+	}
+
+	var opts logrotate.Options
+	opts.MaxSize, _ = cmd.Flags().GetInt64("max-size")
+	opts.MaxAge, _ = cmd.Flags().GetDuration("max-age")
+	opts.MaxBackups, _ = cmd.Flags().GetInt("max-backups")
+	opts.Compress, _ = cmd.Flags().GetBool("compress")
+
+	// Keep writing until the client closes its output, whatever happens to the terminal
+	signal.Ignore(syscall.SIGHUP, syscall.SIGINT)
+
+	writer, err := logrotate.New(dir, name, opts)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, os.Stdin)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func init() {
+	LogWriterCommand.Flags().String("dir", "", "Log directory")
+	LogWriterCommand.Flags().String("name", "", "Client name, used as the log file name")
+	LogWriterCommand.Flags().Int64("max-size", logrotate.DefaultMaxSizeMB*1024*1024, "Rotate once the active file reaches this many bytes")
+	LogWriterCommand.Flags().Duration("max-age", logrotate.DefaultMaxAge, "Rotate once the active file is this old")
+	LogWriterCommand.Flags().Int("max-backups", logrotate.DefaultMaxBackups, "Number of rotated files to keep")
+	LogWriterCommand.Flags().Bool("compress", true, "Gzip rotated files")
+}
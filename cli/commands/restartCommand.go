@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+var RestartCommand = &cobra.Command{
+	Use:   "restart [client]",
+	Short: "Restart running clients",
+	Long: `Restarts a client with its current settings from 'starknode.yaml', or every
+running client if the --all flag is provided.
+
+The old process is stopped gracefully and waited for before the new one is
+started. A client that is not running is simply started.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  restartCommand,
+}
+
+func restartClient(client types.ClientType) {
+	if info := process.GetProcessInfo(string(client)); info != nil {
+		fmt.Println(utils.Cyan(fmt.Sprintf("🔄 Restarting client '%s' (PID %d, waiting up to %s for it to stop)...", client, info.PID, clients.StopTimeout(options.Config, client))))
+	} else {
+		fmt.Println(utils.Cyan(fmt.Sprintf("🚀 Client '%s' is not running, starting it...", client)))
+	}
+
+	pid, err := daemon.RestartClient(options.Config, client)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to restart client '%s': %v", client, err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Client '%s' is running (PID %d).", client, pid)))
+}
+
+// restartAllClients restarts every running client, dependencies first
+func restartAllClients() {
+	records, err := process.ListRecords()
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to read running clients: %v", err)))
+		return
+	}
+	if len(records) == 0 {
+		fmt.Println(utils.Yellow("🤔 No clients are currently running."))
+		return
+	}
+
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+	for _, name := range slices.Backward(clients.StopOrder(options.Config, names)) {
+		restartClient(types.ClientType(name))
+	}
+}
+
+func restartCommand(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ No config found."))
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit config new` to create a config file."))
+		return
+	}
+
+	all, _ := cmd.Flags().GetBool("all")
+	if all {
+		restartAllClients()
+		return
+	}
+
+	if len(args) > 0 {
+		client, err := utils.ResolveClientType(args[0])
+		if err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid client name: %s", args[0])))
+			return
+		}
+		restartClient(client)
+		return
+	}
+
+	fmt.Println(utils.Yellow("Please specify a client to restart or use the --all flag."))
+	cmd.Help()
+}
+
+func init() {
+	RestartCommand.Flags().Bool("all", false, "Restart all running clients")
+}
@@ -1,11 +1,16 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/thebuidl-grid/starknode-kit/cli/options"
 	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 )
@@ -20,7 +25,12 @@ This command starts a single client using its settings from your 'starknode.yaml
 Supported clients:
   - geth, reth (Execution)
   - lighthouse, prysm (Consensus)
-  - juno (Starknet)`,
+  - juno (Starknet)
+
+With --foreground the client stays attached: its output is streamed to stdout,
+SIGINT/SIGTERM stop it gracefully and the command exits non-zero if it dies.
+Otherwise the client must stay up for --grace-period and answer on its RPC or
+health endpoint within --health-timeout, or the command exits non-zero.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if !options.LoadedConfig {
@@ -35,72 +45,81 @@ Supported clients:
 			fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid client name: %s", clientName)))
 			return
 		}
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			client, err := clients.NewClient(options.Config, clientType)
+			if err != nil {
+				fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+				return
+			}
+			printDryRun(cmd, []types.IClient{client})
+			return
+		}
 		if !utils.IsInstalled(clientType) {
 			fmt.Println(utils.Red(fmt.Sprintf("❌ Client %s not installed", clientName)))
 			return
 		}
-		if options.IsClientRunning(clientType) {
+		if err := clients.ValidatePorts(options.Config); err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+		if !options.IsClientRunning(clientType) && !checkPorts(options.Config, clientType) {
+			return
+		}
+		if foreground, _ := cmd.Flags().GetBool("foreground"); foreground {
+			client, err := clients.NewClient(options.Config, clientType)
 			if err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Client already running: %v", err)))
+				fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
 				return
 			}
-			fmt.Println(utils.Cyan("⏳ Waiting for log files to be created..."))
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			stack := []clients.StackClient{{StackEntry: clients.StackEntry{Type: clientType}, Client: client}}
+			runForeground(ctx, options.Config, stack, 0)
+			return
+		}
+		if options.IsClientRunning(clientType) {
+			fmt.Println(utils.Yellow(fmt.Sprintf("⚠️  Client %s is already running, showing its logs.", clientName)))
+			fmt.Println(utils.Yellow(fmt.Sprintf("💡 Use `starknode-kit restart %s` to apply config changes.", clientName)))
 			options.LoadLogs([]string{string(clientType)})
+			return
 		}
 		fmt.Println(utils.Cyan(fmt.Sprintf("🚀 Attempting to run %s...", clientName)))
+		grace, _ := cmd.Flags().GetDuration("grace-period")
+		healthTimeout, _ := cmd.Flags().GetDuration("health-timeout")
 
-		switch clientType {
-		case types.ClientGeth, types.ClientReth:
-			// It's an execution client
-			if options.Config.ExecutionCientSettings.Name != clientType {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Configured execution client is %s, not %s.", options.Config.ExecutionCientSettings.Name, clientName)))
-				return
-			}
-			eClient, err := clients.NewExecutionClient(options.Config.ExecutionCientSettings, options.Config.Network)
-			if err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating execution client: %v", err)))
-				return
-			}
-			if err = eClient.Start(); err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Error starting execution client: %v", err)))
-				return
-			}
-			fmt.Println(utils.Green(fmt.Sprintf("✅ %s started successfully.", clientName)))
-
-		case types.ClientLighthouse, types.ClientPrysm:
-			// It's a consensus client
-			if options.Config.ConsensusCientSettings.Name != clientType {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Configured consensus client is %s, not %s.", options.Config.ConsensusCientSettings.Name, clientName)))
-				return
-			}
-			cClient, err := clients.NewConsensusClient(options.Config.ConsensusCientSettings, options.Config.Network)
-			if err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating consensus client: %v", err)))
-				return
-			}
-			if err = cClient.Start(); err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Error starting consensus client: %v", err)))
-				return
-			}
-			fmt.Println(utils.Green(fmt.Sprintf("✅ %s started successfully.", clientName)))
-
-		case types.ClientJuno:
-			j, err := clients.NewJunoClient(options.Config.JunoConfig, options.Config.Network, options.Config.IsValidatorNode)
+		if c := daemon.Dial(); c != nil {
+			pid, err := c.Start(string(clientType))
 			if err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating Juno client: %v", err)))
+				fmt.Println(utils.Red(fmt.Sprintf("❌ Error starting %s: %v", clientName, err)))
 				return
 			}
-			if err = j.Start(); err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Error starting Juno: %v", err)))
-				return
+			if err := verifyStarted(cmd.Context(), options.Config, clientType, grace, healthTimeout); err != nil {
+				printStartFailure(err)
+				os.Exit(1)
 			}
-			fmt.Println(utils.Green("✅ Juno started successfully."))
+			fmt.Println(utils.Green(fmt.Sprintf("✅ %s started by the daemon (PID %d).", clientName, pid)))
+			fmt.Println(utils.Cyan("⏳ Waiting for log files to be created..."))
+			options.LoadLogs([]string{string(clientType)})
+			return
+		}
 
-		default:
-			fmt.Println(utils.Red(fmt.Sprintf("❌ Don't know how to run client: %s", clientName)))
+		if _, err := clients.StartClient(options.Config, clientType); err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			os.Exit(1)
+		}
+		if err := verifyStarted(cmd.Context(), options.Config, clientType, grace, healthTimeout); err != nil {
+			printStartFailure(err)
+			os.Exit(1)
 		}
+		fmt.Println(utils.Green(fmt.Sprintf("✅ %s started successfully.", clientName)))
 		fmt.Println(utils.Cyan("⏳ Waiting for log files to be created..."))
 		options.LoadLogs([]string{string(clientType)})
 
 	},
 }
+
+func init() {
+	RunCmd.Flags().Bool("foreground", false, "Keep the client attached and stream its output, e.g. as a container entrypoint")
+	addStartupCheckFlags(RunCmd)
+	addDryRunFlags(RunCmd)
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/service"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+var ServiceCommand = &cobra.Command{
+	Use:   "service",
+	Short: "Manage systemd units for the configured clients",
+	Long: `Generates one systemd unit per configured client so the node stack comes back
+after a reboot. Units are ordered execution → consensus → Juno → validator and log
+to journald. Re-run 'service install' after 'config set' to regenerate them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var serviceInstallCommand = &cobra.Command{
+	Use:   "install",
+	Short: "Generate, enable and (re)install systemd units",
+	Run:   serviceInstallCommandRun,
+}
+
+var serviceUninstallCommand = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop, disable and remove the generated systemd units",
+	Run:   serviceUninstallCommandRun,
+}
+
+var serviceStatusCommand = &cobra.Command{
+	Use:   "status",
+	Short: "Show the state of the generated systemd units",
+	Run:   serviceStatusCommandRun,
+}
+
+func serviceInstallCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ No config found."))
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit config new` to create a config file."))
+		return
+	}
+	userScope, _ := cmd.Flags().GetBool("user")
+
+	configured, err := clients.NewConfiguredClients(options.Config)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating clients: %v", err)))
+		return
+	}
+
+	if len(configured) == 0 {
+		fmt.Println(utils.Yellow("🤔 No clients configured."))
+		return
+	}
+
+	units, err := service.Install(configured, userScope)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to install units: %v", err)))
+		return
+	}
+	for _, unit := range units {
+		fmt.Println(utils.Green(fmt.Sprintf("✅ Installed and enabled %s", unit)))
+	}
+	start := "sudo systemctl start"
+	if userScope {
+		start = "systemctl --user start"
+	}
+	fmt.Println(utils.Yellow(fmt.Sprintf("💡 Run `%s %s` to start the stack now.", start, units[len(units)-1])))
+}
+
+func serviceUninstallCommandRun(cmd *cobra.Command, args []string) {
+	userScope, _ := cmd.Flags().GetBool("user")
+
+	units, err := service.Uninstall(userScope)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to uninstall units: %v", err)))
+		return
+	}
+	if len(units) == 0 {
+		fmt.Println(utils.Yellow("🤔 No starknode-kit units installed."))
+		return
+	}
+	for _, unit := range units {
+		fmt.Println(utils.Green(fmt.Sprintf("✅ Removed %s", unit)))
+	}
+}
+
+func serviceStatusCommandRun(cmd *cobra.Command, args []string) {
+	userScope, _ := cmd.Flags().GetBool("user")
+
+	statuses, err := service.Status(userScope)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to read unit status: %v", err)))
+		return
+	}
+	if len(statuses) == 0 {
+		fmt.Println(utils.Yellow("🤔 No starknode-kit units installed."))
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit service install` to generate them."))
+		return
+	}
+
+	fmt.Println(utils.Yellow("--- Service Status ---"))
+	for _, status := range statuses {
+		state := fmt.Sprintf("%s (%s)", status.ActiveState, status.SubState)
+		if status.ActiveState == "active" {
+			state = utils.Green(state)
+		} else {
+			state = utils.Red(state)
+		}
+		fmt.Printf("Unit: %s\n", utils.Blue(status.Unit))
+		fmt.Printf("  Status: %s\n", state)
+		if status.MainPID != "" && status.MainPID != "0" {
+			fmt.Printf("  PID: %s\n", status.MainPID)
+		}
+	}
+}
+
+func init() {
+	ServiceCommand.PersistentFlags().Bool("user", false, "Manage user units (systemctl --user) instead of system units")
+	ServiceCommand.AddCommand(serviceInstallCommand)
+	ServiceCommand.AddCommand(serviceUninstallCommand)
+	ServiceCommand.AddCommand(serviceStatusCommand)
+}
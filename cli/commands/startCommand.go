@@ -1,20 +1,40 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/thebuidl-grid/starknode-kit/cli/options"
 	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/supervisor"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 )
 
 var StartCommand = &cobra.Command{
 	Use:   "start",
 	Short: "Run the configured Ethereum clients",
-	Long: `The run command starts the Ethereum clients (e.g., Prysm, Lighthouse, Geth, etc.)
-that have been added to your local configuration. This executes the clients using the
-defined settings and manages them as part of your node stack.`,
+	Long: `The start command launches the execution and consensus clients that have been
+added to your local configuration, execution client first.
+
+With --all the whole node stack is started in dependency order: execution client,
+consensus client, Juno and the staking validator. Each client is only started once
+the clients it depends on pass their readiness checks (engine API, Juno HTTP RPC,
+Juno fully synced). Each client must then stay up for --grace-period and answer
+on its RPC or health endpoint within --health-timeout, otherwise the end of its
+log is printed and the command exits non-zero.
+
+With --foreground the clients stay attached to the terminal: their output is
+streamed to stdout prefixed with the client name, SIGINT/SIGTERM stops them in
+reverse dependency order, and the command exits non-zero if any client dies.
+This makes it usable as a container entrypoint.`,
 	Run: startCommand,
 }
 
@@ -24,52 +44,219 @@ func startCommand(cmd *cobra.Command, args []string) {
 		fmt.Println(utils.Yellow("💡 Run `starknode-kit config new` to create a config file."))
 		return
 	}
-	el := options.Config.ExecutionCientSettings
-	cl := options.Config.ConsensusCientSettings
-	elClientName := string(el.Name)
-	clClientName := string(cl.Name)
+	all, _ := cmd.Flags().GetBool("all")
+	readyTimeout, _ := cmd.Flags().GetDuration("ready-timeout")
+	grace, _ := cmd.Flags().GetDuration("grace-period")
+	healthTimeout, _ := cmd.Flags().GetDuration("health-timeout")
 
-	// Validate clients are installed
-	elClientType, err := utils.GetExecutionClient(elClientName)
-	if err != nil {
-		fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid execution client in config: %%v", err)))
-		return
+	cfg := options.Config
+	if !all {
+		// Without --all only the Ethereum clients are started
+		cfg.JunoConfig = types.JunoConfig{}
+		cfg.IsValidatorNode = false
 	}
-	clClientType, err := utils.GetConsensusClient(clClientName)
-	if err != nil {
-		fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid consensus client in config: %%v", err)))
+
+	if _, err := utils.GetExecutionClient(string(cfg.ExecutionCientSettings.Name)); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid execution client in config: %v", err)))
 		return
 	}
-	if !utils.IsInstalled(elClientType) {
-		fmt.Println(utils.Yellow(fmt.Sprintf("🤔 Client '%s' is not installed.", elClientType)))
-		fmt.Printf("Please run: starknode-kit add -e %s\n", elClientType)
+	if _, err := utils.GetConsensusClient(string(cfg.ConsensusCientSettings.Name)); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid consensus client in config: %v", err)))
 		return
 	}
-	if !utils.IsInstalled(clClientType) {
-		fmt.Println(utils.Yellow(fmt.Sprintf("🤔 Client '%s' is not installed.", clClientType)))
-		fmt.Printf("Please run: starknode-kit add -c %s\n", clClientType)
+	if err := clients.ValidatePorts(cfg); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
 		return
 	}
 
-	fmt.Println(utils.Cyan("🚀 Starting consensus and execution clients in the background..."))
-	cClient, err := clients.NewConsensusClient(cl, options.Config.Network)
+	order, err := clients.StackOrder(cfg)
 	if err != nil {
-		fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating consensus client: %%v", err)))
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
 		return
 	}
-	eClient, err := clients.NewExecutionClient(el, options.Config.Network)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	for _, entry := range order {
+		if !dryRun && !utils.IsInstalled(entry.Type) {
+			fmt.Println(utils.Yellow(fmt.Sprintf("🤔 Client '%s' is not installed.", entry.Type)))
+			fmt.Printf("Please run: starknode-kit add %s %s\n", installFlag(entry.Type), entry.Type)
+			return
+		}
+	}
+
+	stack, err := clients.NewStack(cfg)
 	if err != nil {
-		fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating execution client: %%v", err)))
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating clients: %v", err)))
 		return
 	}
-
-	if err = cClient.Start(); err != nil {
-		fmt.Println(utils.Red(fmt.Sprintf("❌ Error starting consensus client: %%v", err)))
+	if dryRun {
+		launch := make([]types.IClient, 0, len(stack))
+		for _, c := range stack {
+			launch = append(launch, c.Client)
+		}
+		printDryRun(cmd, launch)
 		return
 	}
-	if err = eClient.Start(); err != nil {
-		fmt.Println(utils.Red(fmt.Sprintf("❌ Error starting execution client: %%v", err)))
+	for _, c := range stack {
+		if process.GetProcessInfo(string(c.Type)) == nil && !checkPorts(cfg, c.Type) {
+			return
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if foreground, _ := cmd.Flags().GetBool("foreground"); foreground {
+		runForeground(ctx, cfg, stack, readyTimeout)
 		return
 	}
+
+	fmt.Println(utils.Cyan("🚀 Starting clients in the background..."))
+	if err := startStack(ctx, cfg, stack, readyTimeout, grace, healthTimeout); err != nil {
+		printStartFailure(err)
+		fmt.Println(utils.Yellow("💡 Clients that were already started keep running. Use `starknode-kit stop --all` to stop them."))
+		os.Exit(1)
+	}
 	fmt.Println(utils.Green("✅ Clients started successfully in the background."))
 }
+
+// startStack starts each client once all of its dependencies report ready and
+// makes sure it stays up and answers before moving on
+func startStack(ctx context.Context, cfg types.StarkNodeKitConfig, stack []clients.StackClient, readyTimeout, grace, healthTimeout time.Duration) error {
+	ready := make(map[types.ClientType]bool)
+
+	for _, c := range stack {
+		for _, dep := range c.DependsOn {
+			if ready[dep] {
+				continue
+			}
+			waitCtx, cancel := context.WithTimeout(ctx, readyTimeout)
+			err := clients.WaitReady(waitCtx, cfg, dep, func(probe clients.ReadinessProbe) {
+				fmt.Println(utils.Cyan(fmt.Sprintf("⏳ Waiting for %s: %s", dep, probe.Description)))
+			})
+			cancel()
+			if err != nil {
+				return fmt.Errorf("not starting %s: %w", c.Type, err)
+			}
+			ready[dep] = true
+		}
+
+		if info := process.GetProcessInfo(string(c.Type)); info != nil {
+			fmt.Println(utils.Yellow(fmt.Sprintf("💡 Client '%s' is already running (PID %d).", c.Type, info.PID)))
+			continue
+		}
+		if _, err := clients.StartClient(cfg, c.Type); err != nil {
+			return err
+		}
+		if err := verifyStarted(ctx, cfg, c.Type, grace, healthTimeout); err != nil {
+			return err
+		}
+		fmt.Println(utils.Green(fmt.Sprintf("✅ Started %s", c.Type)))
+	}
+	return nil
+}
+
+// runForeground keeps the clients attached until a signal arrives or one of
+// them exits, and exits non-zero in the latter case
+func runForeground(ctx context.Context, cfg types.StarkNodeKitConfig, stack []clients.StackClient, readyTimeout time.Duration) {
+	fmt.Println(utils.Cyan("🚀 Starting clients in the foreground. Press Ctrl+C to stop."))
+	if err := supervisor.RunForeground(ctx, cfg, stack, os.Stdout, readyTimeout); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		os.Exit(1)
+	}
+	fmt.Println(utils.Green("✅ All clients stopped."))
+}
+
+// verifyStarted waits for a freshly started client to prove it is alive.
+// A zero grace period skips the check.
+func verifyStarted(ctx context.Context, cfg types.StarkNodeKitConfig, client types.ClientType, grace, healthTimeout time.Duration) error {
+	if grace <= 0 {
+		return nil
+	}
+	fmt.Println(utils.Cyan(fmt.Sprintf("⏳ Checking that %s stays up...", client)))
+	return clients.VerifyStarted(ctx, cfg, client, grace, healthTimeout)
+}
+
+// printStartFailure prints why a client could not be started together with
+// the end of its log when it died right after starting
+func printStartFailure(err error) {
+	fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+
+	var startupErr *clients.StartupError
+	if !errors.As(err, &startupErr) || startupErr.LogFile == "" {
+		return
+	}
+	lines, err := process.TailLog(startupErr.LogFile, startupLogLines)
+	if err != nil || len(lines) == 0 {
+		return
+	}
+	fmt.Println(utils.Yellow(fmt.Sprintf("📄 Last lines of %s:", startupErr.LogFile)))
+	for _, line := range lines {
+		fmt.Println("   " + line)
+	}
+}
+
+// checkPorts makes sure every port the client is about to bind is free and
+// warns about RPC listeners exposed on all interfaces. It returns false if the
+// client cannot start.
+func checkPorts(cfg types.StarkNodeKitConfig, client types.ClientType) bool {
+	conflicts, err := clients.CheckPorts(cfg, client)
+	if err != nil {
+		fmt.Println(utils.Yellow(fmt.Sprintf("⚠️  Could not check the ports of %s: %v", client, err)))
+	}
+	if len(conflicts) > 0 {
+		for _, conflict := range conflicts {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %s", conflict)))
+		}
+		fmt.Println(utils.Yellow(fmt.Sprintf("💡 Stop the other process or change the port with `starknode-kit config set %s`.", configTarget(client))))
+		return false
+	}
+
+	if exposed := clients.ExposedListeners(cfg, client); len(exposed) > 0 {
+		for _, l := range exposed {
+			fmt.Println(utils.Yellow(fmt.Sprintf("⚠️  %s %s listens on all interfaces (%s:%d)", client, l.Name, l.Address, l.Port)))
+		}
+		fmt.Println(utils.Yellow(fmt.Sprintf("💡 Make sure a firewall blocks these ports, or run `starknode-kit config set %s bind_address=127.0.0.1`.", configTarget(client))))
+	}
+	return true
+}
+
+// configTarget returns the `config set` subcommand for a client
+func configTarget(client types.ClientType) string {
+	switch client {
+	case types.ClientGeth, types.ClientReth:
+		return "el"
+	case types.ClientLighthouse, types.ClientPrysm:
+		return "cl"
+	default:
+		return "starknet"
+	}
+}
+
+// startupLogLines is how much of a client's log is shown when it fails to start
+const startupLogLines = 20
+
+// addStartupCheckFlags adds the flags controlling the post-start liveness check
+func addStartupCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("grace-period", 10*time.Second, "How long a started client must stay up before it counts as started, 0 disables the check")
+	cmd.Flags().Duration("health-timeout", 2*time.Minute, "How long a started client has to answer on its RPC or health endpoint")
+}
+
+// installFlag returns the `add` flag used to install a client
+func installFlag(client types.ClientType) string {
+	switch client {
+	case types.ClientGeth, types.ClientReth:
+		return "-e"
+	case types.ClientLighthouse, types.ClientPrysm:
+		return "-c"
+	default:
+		return "-s"
+	}
+}
+
+func init() {
+	StartCommand.Flags().Bool("all", false, "Start the whole node stack (Ethereum clients, Juno and the validator) in dependency order")
+	StartCommand.Flags().Bool("foreground", false, "Keep the clients attached and stream their output, e.g. as a container entrypoint")
+	StartCommand.Flags().Duration("ready-timeout", 30*time.Minute, "How long to wait for each dependency to become ready")
+	addStartupCheckFlags(StartCommand)
+	addDryRunFlags(StartCommand)
+}
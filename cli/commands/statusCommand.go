@@ -2,10 +2,15 @@ package commands
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/stats"
+	"github.com/thebuidl-grid/starknode-kit/pkg/supervisor"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 	"github.com/thebuidl-grid/starknode-kit/pkg/versions"
@@ -25,14 +30,26 @@ func statusCommand(cmd *cobra.Command, args []string) {
 
 	fmt.Println(utils.Yellow("--- Client Status ---"))
 
+	managed := daemonManagedClients()
+
 	if len(args) == 0 {
-		runningClients := utils.GetRunningClients()
-		if len(runningClients) == 0 {
+		records, _ := process.ListRecords()
+		var names []string
+		for _, record := range records {
+			names = append(names, record.Name)
+		}
+		// Managed clients waiting to be restarted have no record
+		for name := range managed {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
 			fmt.Println(utils.Red("❌ No client running"))
 			return
 		}
-		for _, clientType := range runningClients {
-			displayClientStatus(types.ClientType(clientType.Name))
+		for _, name := range names {
+			displayClientStatus(types.ClientType(name), managed)
 		}
 	} else {
 		clientName := args[0]
@@ -41,11 +58,33 @@ func statusCommand(cmd *cobra.Command, args []string) {
 			fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid client name: %s", clientName)))
 			return
 		}
-		displayClientStatus(clientType)
+		displayClientStatus(clientType, managed)
+	}
+}
+
+// daemonManagedClients returns the clients supervised by the daemon, or nil if
+// no daemon is running
+func daemonManagedClients() map[string]daemon.ClientStatus {
+	c := daemon.Dial()
+	if c == nil {
+		return nil
+	}
+	statuses, err := c.Status()
+	if err != nil {
+		fmt.Println(utils.Yellow(fmt.Sprintf("⚠️  Could not query the daemon: %v", err)))
+		return nil
+	}
+	fmt.Printf("Daemon: %s (%s)\n", utils.Green("Running"), daemon.SocketPath())
+	managed := make(map[string]daemon.ClientStatus)
+	for _, status := range statuses {
+		if status.Managed {
+			managed[status.Name] = status
+		}
 	}
+	return managed
 }
 
-func displayClientStatus(clientType types.ClientType) {
+func displayClientStatus(clientType types.ClientType, managed map[string]daemon.ClientStatus) {
 	clientName := string(clientType)
 	fmt.Printf("Client: %s\n", utils.Blue(clientName))
 
@@ -57,11 +96,90 @@ func displayClientStatus(clientType types.ClientType) {
 	version := versions.GetVersionNumber(clientName)
 	fmt.Printf("  Version: %s\n", utils.Green(version))
 
-	processInfo := process.GetProcessInfo(clientName)
+	processInfo := process.GetProcessUsage(clientName)
 	if processInfo != nil {
 		fmt.Printf("  Status: %s (PID: %d)\n", utils.Green("Running"), processInfo.PID)
 		fmt.Printf("  Uptime: %s\n", utils.Green(processInfo.Uptime.Round(time.Second).String()))
+		displayResourceUsage(processInfo)
 	} else {
 		fmt.Printf("  Status: %s\n", utils.Red("Stopped"))
 	}
+	if status, ok := managed[clientName]; ok {
+		fmt.Printf("  Managed by daemon: %s\n", utils.Green(status.State))
+	}
+
+	displaySupervisorHistory(strings.ToLower(clientName))
+}
+
+// cpuSampleInterval is how long CPU usage is measured for when showing status
+const cpuSampleInterval = 500 * time.Millisecond
+
+// displayResourceUsage prints the CPU, memory, disk IO and open files of a
+// client's process tree
+func displayResourceUsage(info *types.ProcessInfo) {
+	if cpu, err := process.MeasureCPU(info.PID, cpuSampleInterval); err == nil {
+		info.CPUUsage = cpu
+	}
+	fmt.Printf("  CPU: %s\n", utils.Green(fmt.Sprintf("%.1f%%", info.CPUUsage)))
+	memory := stats.FormatBytes(info.MemUsage)
+	if info.MemLimit > 0 {
+		memory = fmt.Sprintf("%s / %s (cgroup %s)", memory, stats.FormatBytes(info.MemLimit), stats.FormatBytes(info.CgroupMemory))
+	}
+	fmt.Printf("  Memory: %s\n", utils.Green(memory))
+	fmt.Printf("  Disk IO: %s\n", utils.Green(fmt.Sprintf("%s read, %s written", stats.FormatBytes(info.DiskRead), stats.FormatBytes(info.DiskWrite))))
+	openFiles := fmt.Sprintf("%d", info.OpenFDs)
+	if info.FDLimit > 0 {
+		openFiles = fmt.Sprintf("%d / %d", info.OpenFDs, info.FDLimit)
+	}
+	fmt.Printf("  Open files: %s\n", utils.Green(openFiles))
+	if info.Processes > 1 {
+		fmt.Printf("  Processes: %s\n", utils.Green(fmt.Sprintf("%d (including children)", info.Processes)))
+	}
+	displayLimits(info)
+}
+
+// displayLimits prints the scheduling limits of a client that differ from the defaults
+func displayLimits(info *types.ProcessInfo) {
+	if info.Nice != 0 {
+		fmt.Printf("  Nice: %s\n", utils.Green(fmt.Sprintf("%d", info.Nice)))
+	}
+	if info.IOClass != "" {
+		fmt.Printf("  IO class: %s\n", utils.Green(info.IOClass))
+	}
+	if info.CPUWeight > 0 && info.CPUWeight != 100 {
+		fmt.Printf("  CPU weight: %s\n", utils.Green(fmt.Sprintf("%d", info.CPUWeight)))
+	}
+	if info.Cgroup != "" && (info.MemLimit > 0 || info.CPUWeight > 0) {
+		fmt.Printf("  Cgroup: %s\n", utils.Green(info.Cgroup))
+	}
+}
+
+// displaySupervisorHistory prints the restart history recorded by `starknode-kit supervise`
+func displaySupervisorHistory(clientName string) {
+	history, err := process.LoadHistory(clientName)
+	if err != nil || history == nil {
+		return
+	}
+
+	state := utils.Green(history.State)
+	if history.State == "crash-loop" {
+		state = utils.Red(history.State)
+	}
+	fmt.Printf("  Supervisor: %s (restarts: %d)\n", state, history.Restarts)
+
+	if len(history.Exits) == 0 {
+		return
+	}
+	exits := history.Exits[max(len(history.Exits)-5, 0):]
+	fmt.Println("  Recent exits:")
+	for _, exit := range exits {
+		fmt.Printf("    %s  PID %d %s\n", exit.ExitedAt.Format(time.DateTime), exit.PID, supervisor.DescribeExit(exit))
+	}
+	last := exits[len(exits)-1]
+	if len(last.LogTail) > 0 {
+		fmt.Println("  Last log lines before exit:")
+		for _, line := range last.LogTail {
+			fmt.Printf("    %s\n", line)
+		}
+	}
 }
@@ -2,10 +2,16 @@ package commands
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 )
 
@@ -20,34 +26,67 @@ Provide a client name (e.g., geth, lighthouse, juno) to stop a single client.`,
 }
 
 func stopClient(clientName string) {
-	processInfo := process.GetProcessInfo(clientName)
+	client := types.ClientType(strings.ToLower(clientName))
+	processInfo := process.GetProcessInfo(string(client))
 	if processInfo == nil {
 		fmt.Println(utils.Yellow(fmt.Sprintf("🤔 Client '%s' is not running.", clientName)))
 		return
 	}
 
-	fmt.Printf("🛑 Stopping client '%s' (PID %d)...", processInfo.Name, processInfo.PID)
-	err := process.StopClient(processInfo.PID)
+	timeout := clients.StopTimeout(options.Config, client)
+	fmt.Println(utils.Cyan(fmt.Sprintf("🛑 Stopping client '%s' (PID %d, waiting up to %s)...", processInfo.Name, processInfo.PID, timeout)))
+	result, err := daemon.StopClient(options.Config, client)
 	if err != nil {
-		if err.Error() != "os: process already finished" {
-			fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to stop client '%s': %v", processInfo.Name, err)))
-		}
-	} else {
-		fmt.Println(utils.Green(fmt.Sprintf("✅ Client '%s' stopped successfully.", processInfo.Name)))
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to stop client '%s': %v", processInfo.Name, err)))
+		return
+	}
+	printStopResult(processInfo.Name, result, timeout)
+}
+
+// printStopResult reports whether a client shut down on its own or had to be killed
+func printStopResult(name string, result *types.StopResult, timeout time.Duration) {
+	if result == nil {
+		fmt.Println(utils.Yellow(fmt.Sprintf("🤔 Client '%s' is not running.", name)))
+		return
+	}
+	switch result.Method {
+	case "killed":
+		fmt.Println(utils.Yellow(fmt.Sprintf("⚠️  Client '%s' did not exit within %s and was killed.", name, timeout)))
+	case "exited":
+		fmt.Println(utils.Green(fmt.Sprintf("✅ Client '%s' had already exited.", name)))
+	default:
+		fmt.Println(utils.Green(fmt.Sprintf("✅ Client '%s' stopped gracefully in %s.", name, result.Duration.Round(time.Millisecond))))
 	}
 }
 
 func stopAllClients() {
 	fmt.Println(utils.Cyan("🔍 Stopping all running clients..."))
 
-	runningClients := utils.GetRunningClients()
-	if len(runningClients) == 0 {
+	records, err := process.ListRecords()
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to read running clients: %v", err)))
+		return
+	}
+	if len(records) == 0 {
 		fmt.Println(utils.Green("✅ No clients are currently running."))
 		return
 	}
 
-	for _, client := range runningClients {
-		stopClient(client.Name)
+	// Tear down in reverse dependency order so no client outlives what it depends on.
+	// Clients that are no longer in the config go last, most recently started first.
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartedAt.After(records[j].StartedAt)
+	})
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+	order := clients.StopOrder(options.Config, names)
+
+	for _, name := range order {
+		if process.GetProcessInfo(name) != nil {
+			stopClient(name)
+		}
 	}
 }
 
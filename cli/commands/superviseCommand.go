@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/supervisor"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+var SuperviseCommand = &cobra.Command{
+	Use:   "supervise",
+	Short: "Run all configured clients under a supervisor",
+	Long: `Starts every configured client (execution, consensus, Juno and the staking
+validator) and keeps them running in the foreground.
+
+When a client exits, its exit code or signal and the last lines of its log are
+recorded and it is restarted with exponential backoff. A client that keeps
+crashing is given up on after --crash-loop-limit exits. The history is shown
+by 'starknode-kit status'.`,
+	Run: superviseCommand,
+}
+
+func superviseCommand(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ No config found."))
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit config new` to create a config file."))
+		return
+	}
+
+	opts := supervisor.DefaultOptions()
+	opts.InitialBackoff, _ = cmd.Flags().GetDuration("backoff")
+	opts.MaxBackoff, _ = cmd.Flags().GetDuration("max-backoff")
+	opts.CrashLoopLimit, _ = cmd.Flags().GetInt("crash-loop-limit")
+	opts.CrashLoopWindow, _ = cmd.Flags().GetDuration("crash-loop-window")
+	opts.LogTailLines, _ = cmd.Flags().GetInt("log-lines")
+
+	configured, err := clients.NewConfiguredClients(options.Config)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating clients: %v", err)))
+		os.Exit(1)
+	}
+	if len(configured) == 0 {
+		fmt.Println(utils.Yellow("🤔 No clients configured."))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		fmt.Println(utils.Cyan("\n🛑 Stopping supervised clients..."))
+		cancel()
+	}()
+
+	fmt.Println(utils.Cyan("🚀 Supervising configured clients. Press Ctrl+C to stop."))
+	if err := supervisor.New(configured, opts).Run(ctx); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		os.Exit(1)
+	}
+	fmt.Println(utils.Green("✅ All supervised clients stopped."))
+}
+
+func init() {
+	defaults := supervisor.DefaultOptions()
+	SuperviseCommand.Flags().Duration("backoff", defaults.InitialBackoff, "Delay before the first restart")
+	SuperviseCommand.Flags().Duration("max-backoff", defaults.MaxBackoff, "Maximum delay between restarts")
+	SuperviseCommand.Flags().Int("crash-loop-limit", defaults.CrashLoopLimit, "Exits within the crash loop window before a client is given up on")
+	SuperviseCommand.Flags().Duration("crash-loop-window", defaults.CrashLoopWindow, "Window used to detect crash loops")
+	SuperviseCommand.Flags().Int("log-lines", defaults.LogTailLines, "Log lines captured with every exit")
+}
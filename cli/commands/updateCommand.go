@@ -5,7 +5,10 @@ import (
 	"strings"
 
 	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/updater"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 
@@ -61,11 +64,11 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		fmt.Println(utils.Cyan("⏳ Fetching latest versions from GitHub..."))
 	}
 
-	clients := append(eth_clients, stark_clients...)
+	installed := append(eth_clients, stark_clients...)
 
 	// Check for updates
 	var updatesAvailable []updater.UpdateInfo
-	for _, client := range clients {
+	for _, client := range installed {
 		updateInfo, err := updateChecker.CheckClientForUpdate(string(client), true)
 		if err != nil {
 			fmt.Println(utils.Yellow(fmt.Sprintf("⚠️  Warning: Could not check %s: %v", client, err)))
@@ -117,12 +120,26 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	for _, update := range updatesAvailable {
 		fmt.Printf("\n⬆️  Updating %s...\n", update.Client)
 
+		client := types.ClientType(update.Client)
+		pid := 0
+		if info := process.GetProcessInfo(update.Client); info != nil {
+			pid = info.PID
+		}
+		if err := clients.RunHook(options.Config, client, clients.HookPreUpdate, pid); err != nil {
+			failed++
+			fmt.Println(utils.Red(fmt.Sprintf("❌ Skipping %s: %v", update.Client, err)))
+			continue
+		}
+
 		result := updateChecker.UpdateClient(update.Client)
 
 		if result.Success {
 			successful++
 			fmt.Println(utils.Green(fmt.Sprintf("✅ %s updated successfully: %s → %s",
 				update.Client, result.PreviousVersion, result.NewVersion)))
+			if err := clients.RunHook(options.Config, client, clients.HookPostUpdate, pid); err != nil {
+				fmt.Println(utils.Yellow(fmt.Sprintf("⚠️  %v", err)))
+			}
 		} else {
 			failed++
 			fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to update %s: %s", update.Client, result.Error)))
@@ -1,20 +1,27 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/spf13/cobra"
 	"github.com/thebuidl-grid/starknode-kit/cli/options"
 	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
+	"github.com/thebuidl-grid/starknode-kit/pkg/keystore"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 	"github.com/thebuidl-grid/starknode-kit/pkg/validator"
 	"github.com/thebuidl-grid/starknode-kit/pkg/versions"
+	"github.com/thebuidl-grid/starknode-kit/pkg/wallet"
 )
 
 var rpcProvider *rpc.Provider
@@ -28,7 +35,6 @@ var ValidatorCommand = &cobra.Command{
 			return
 		}
 		versionFlag, _ := cmd.Flags().GetBool("version")
-		rpc_url, _ := cmd.Flags().GetString("rpc")
 		if versionFlag {
 			if !utils.IsInstalled(types.ClientStarkValidator) {
 				fmt.Println(utils.Yellow(fmt.Sprintf("🤔 Client %s is not installed.", types.ClientStarkValidator)))
@@ -39,43 +45,34 @@ var ValidatorCommand = &cobra.Command{
 			fmt.Printf("%s version: %s\n", clientName, utils.Green(version))
 			return
 		}
-		if rpc_url != "" {
-			var err error
-			url, err := url.ParseRequestURI(rpc_url)
-			if err != nil {
-				fmt.Printf("invalid URL format for rpc_url: '%s'", utils.Red(rpc_url))
-				return
-			}
-			switch url.Scheme {
-			case "https", "http":
-				options.Config.ValidatorConfig.ProviderConfig.JunoRPC = rpc_url
-				fmt.Printf("Successfully set Provider HTTP url to '%s'\n", utils.Green(rpc_url))
-			case "wss", "ws":
-				options.Config.ValidatorConfig.ProviderConfig.JunoWS = rpc_url
-				fmt.Printf("Successfully set Provider WS url to '%s'\n", utils.Green(rpc_url))
-			default:
-				err = fmt.Errorf("Inalid scheme '%s'", utils.Red(url.Scheme))
-			}
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
-			if err := utils.UpdateStarkNodeConfig(options.Config); err != nil {
-				fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to save config: %v", err)))
-			}
-		}
 	},
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		if cmd.Parent() != nil && cmd.Parent().PersistentPreRun != nil {
 			cmd.Parent().PersistentPreRun(cmd.Parent(), args)
 		}
+		// Keep stdout parseable for commands with --json
+		asJSON, _ := cmd.Flags().GetBool("json")
 		if !options.Config.IsValidatorNode {
+			if asJSON {
+				exitJSONError(errors.New("this is not a validator node, check your configuration"))
+			}
 			fmt.Println(utils.Red("❌ This is not a validator node. Check your configuration."))
 			os.Exit(1)
 		}
+		if options.Config.Wallet.Wallet.Keystore == "" && keystore.HasPlaintextKey(constants.EnvFIlePath) {
+			out := os.Stdout
+			if asJSON {
+				out = os.Stderr
+			}
+			fmt.Fprintln(out, utils.Yellow("⚠️ The wallet's private key is stored unencrypted in .starknode.env."))
+			fmt.Fprintln(out, utils.Yellow("💡 Run `starknode-kit wallet migrate` to move it into a password protected keystore."))
+		}
 		var err error
 		rpcProvider, err = utils.CreateRPCProvider(options.Config.Network)
 		if err != nil {
+			if asJSON {
+				exitJSONError(fmt.Errorf("error creating RPC provider: %w", err))
+			}
 			fmt.Printf(utils.Red("❌ Error creating RPC provider: %v\n"), err)
 			os.Exit(1)
 		}
@@ -109,6 +106,111 @@ var validatorStatusCommand = &cobra.Command{
 	Run:   validatorStatusCommandRun,
 }
 
+var validatorClaimRewardsCommand = &cobra.Command{
+	Use:   "claim-rewards",
+	Short: "Claim the validator's staking rewards",
+	Long: `Claims the unclaimed staking rewards of the configured wallet. They are sent to
+the reward address registered with the staking contract.`,
+	Run: validatorClaimRewardsCommandRun,
+}
+
+var validatorIncreaseStakeCommand = &cobra.Command{
+	Use:   "increase-stake",
+	Short: "Add STRK to the validator's stake",
+	Long: `Adds STRK from the configured wallet to the validator's stake. If the staking
+contract's allowance is lower than the amount, it is approved in the same transaction.`,
+	Run: validatorIncreaseStakeCommandRun,
+}
+
+var validatorUnstakeCommand = &cobra.Command{
+	Use:   "unstake",
+	Short: "Leave the staking protocol",
+	Long: `Leaving the staking protocol takes two transactions. "unstake intent" stops the stake
+from earning rewards and opens an exit window. Once it has passed, "unstake action"
+withdraws the stake.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var validatorUnstakeIntentCommand = &cobra.Command{
+	Use:   "intent",
+	Short: "Signal the intent to unstake and stop the validator",
+	Run:   validatorUnstakeIntentCommandRun,
+}
+
+var validatorUnstakeStatusCommand = &cobra.Command{
+	Use:   "status",
+	Short: "Show when the stake can be withdrawn",
+	Run:   validatorUnstakeStatusCommandRun,
+}
+
+var validatorUnstakeActionCommand = &cobra.Command{
+	Use:   "action",
+	Short: "Withdraw the stake once the exit window has passed",
+	Run:   validatorUnstakeActionCommandRun,
+}
+
+var validatorSetCommand = &cobra.Command{
+	Use:   "set",
+	Short: "Change the validator's settings on the staking contract",
+	Long: `Changes a setting of the staker on the staking contract, then saves it to
+starknode.yaml. The config is only updated once the transaction is confirmed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var validatorSetRewardAddressCommand = &cobra.Command{
+	Use:   "reward-address <address>",
+	Short: "Change the address rewards are paid to",
+	Args:  cobra.ExactArgs(1),
+	Run:   validatorSetRewardAddressCommandRun,
+}
+
+var validatorSetOperationalAddressCommand = &cobra.Command{
+	Use:   "operational-address <wallet>",
+	Short: "Sign for the validator with another wallet",
+	Long: `Makes an imported wallet the operational account of the staker. The wallet first
+declares the staker it works for, then the staker switches to it. A running validator
+client is restarted with the new signer.`,
+	Args: cobra.ExactArgs(1),
+	Run:  validatorSetOperationalAddressCommandRun,
+}
+
+var validatorSetCommissionCommand = &cobra.Command{
+	Use:   "commission <percent>",
+	Short: "Change the commission taken from delegators' rewards",
+	Args:  cobra.ExactArgs(1),
+	Run:   validatorSetCommissionCommandRun,
+}
+
+var validatorPoolCommand = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage the validator's STRK delegation pool",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var validatorPoolOpenCommand = &cobra.Command{
+	Use:   "open",
+	Short: "Open a STRK delegation pool so others can delegate to the validator",
+	Run:   validatorPoolOpenCommandRun,
+}
+
+var validatorPoolInfoCommand = &cobra.Command{
+	Use:   "info",
+	Short: "Show the pool address, total delegated and commission",
+	Run:   validatorPoolInfoCommandRun,
+}
+
+var validatorPoolMembersCommand = &cobra.Command{
+	Use:   "members",
+	Short: "List the pool's delegators and their balances",
+	Run:   validatorPoolMembersCommandRun,
+}
+
 func validatorInfoCommandRun(cmd *cobra.Command, args []string) {
 	if !options.LoadedConfig {
 		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
@@ -127,6 +229,9 @@ func validatorInfoCommandRun(cmd *cobra.Command, args []string) {
 	utils.PrintKV("Operational Address", validatorInfo.OperationalAddress)
 	utils.PrintKV("Total Staked", fmt.Sprintf("%.4f STRK", validatorInfo.TotalStaked))
 	utils.PrintKV("Unclaimed Rewards", fmt.Sprintf("%.4f STRK", validatorInfo.UnclaimedRewards))
+	if !validatorInfo.UnstakeTime.IsZero() {
+		utils.PrintKV("Unstaking, Withdrawable From", validatorInfo.UnstakeTime.Format(time.DateTime))
+	}
 }
 
 func validatorStopCommandRun(cmd *cobra.Command, args []string) {
@@ -139,12 +244,14 @@ func validatorStopCommandRun(cmd *cobra.Command, args []string) {
 		fmt.Println(utils.Yellow("Validator client is not running."))
 		return
 	}
-	err := process.StopClient(processInfo.PID)
+	timeout := clients.StopTimeout(options.Config, types.ClientStarkValidator)
+	// Through the daemon when it supervises the validator, or it would be restarted
+	result, err := daemon.StopClient(options.Config, types.ClientStarkValidator)
 	if err != nil {
 		fmt.Printf(utils.Red("Could not stop validator process: %v\n"), err)
 		return
 	}
-	fmt.Println(utils.Green("✅ Validator client stopped successfully."))
+	printStopResult("validator", result, timeout)
 }
 
 func validatorStartCommandRun(cmd *cobra.Command, args []string) {
@@ -153,14 +260,20 @@ func validatorStartCommandRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	fmt.Println(utils.Cyan("🚀 Starting Validator client..."))
-	validatorNode, err := clients.NewValidatorClient(options.Config.ValidatorConfig)
+	validatorNode, err := clients.NewValidatorClient(options.Config.ValidatorConfig, options.Config.JunoConfig)
 	if err != nil {
 		fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating validator client: %v", err)))
 		return
 	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		printDryRun(cmd, []types.IClient{validatorNode})
+		return
+	}
+
+	fmt.Println(utils.Cyan("🚀 Starting Validator client..."))
 	if !options.IsClientRunning(types.ClientStarkValidator) {
-		err = validatorNode.Start()
+		// Through the daemon when it is running, so the validator is supervised
+		_, err = daemon.StartClient(options.Config, types.ClientStarkValidator)
 		if err != nil {
 			fmt.Println(utils.Red(fmt.Sprintf("❌ Error starting validator client: %v", err)))
 			return
@@ -193,18 +306,335 @@ func validatorBalanceCommandRun(cmd *cobra.Command, args []string) {
 	fmt.Printf("%s %.4f STRK\n", utils.Green("✅ Validator Balance:"), balance)
 }
 
+func validatorClaimRewardsCommandRun(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	if !options.LoadedConfig {
+		if asJSON {
+			exitJSONError(errors.New("config not found, run `starknode-kit config new`"))
+		}
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	if asJSON {
+		validator.SetProgressOutput(os.Stderr)
+	}
+
+	result, err := validator.ClaimRewards(rpcProvider, options.Config.Wallet.Wallet, dryRun)
+	noRewards := errors.Is(err, validator.ErrNoRewards)
+	if err != nil && !noRewards {
+		if asJSON {
+			exitJSONError(err)
+		}
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error claiming rewards: %v", err)))
+		return
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			exitJSONError(err)
+		}
+		return
+	}
+	if noRewards {
+		fmt.Println(utils.Yellow("🤔 There are no rewards to claim."))
+		return
+	}
+
+	if dryRun {
+		fmt.Println(utils.Yellow("👀 Dry run, no transaction was sent."))
+		utils.PrintKV("Unclaimed Rewards", fmt.Sprintf("%.4f STRK", result.UnclaimedBefore))
+		utils.PrintKV("Reward Address", result.RewardAddress)
+		utils.PrintKV("Estimated Fee", fmt.Sprintf("%.6f STRK", result.EstimatedFee))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Claimed %.4f STRK to %s", result.UnclaimedBefore-result.UnclaimedAfter, result.RewardAddress)))
+	utils.PrintKV("Unclaimed Rewards", fmt.Sprintf("%.4f → %.4f STRK", result.UnclaimedBefore, result.UnclaimedAfter))
+	utils.PrintKV("Reward Address Balance", fmt.Sprintf("%.4f → %.4f STRK", result.RewardBalanceBefore, result.RewardBalanceAfter))
+	utils.PrintKV("Transaction", result.TransactionHash)
+}
+
+// exitJSONError reports an error as {"error": "..."} for --json output and
+// exits with status 1
+func exitJSONError(err error) {
+	json.NewEncoder(os.Stdout).Encode(map[string]string{"error": err.Error()})
+	os.Exit(1)
+}
+
+func validatorIncreaseStakeCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	amountFlag, _ := cmd.Flags().GetString("amount")
+	if amountFlag == "" {
+		fmt.Println(utils.Red("❌ Pass the STRK amount to add with --amount."))
+		return
+	}
+	amount, err := validator.ParseSTRK(amountFlag)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+
+	fmt.Println(utils.Cyan(fmt.Sprintf("🚀 Adding %s STRK to the stake...", amountFlag)))
+	if err := validator.IncreaseStake(rpcProvider, options.Config.Wallet.Wallet, amount); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error increasing stake: %v", err)))
+		return
+	}
+
+	validatorInfo, err := validator.GetValidatorInfo(rpcProvider, options.Config.Wallet.Wallet)
+	if err != nil {
+		fmt.Println(utils.Yellow(fmt.Sprintf("⚠️ Stake increased but failed to read the new total: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Stake increased, total staked: %.4f STRK", validatorInfo.TotalStaked)))
+}
+
+func validatorUnstakeIntentCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		fmt.Println(utils.Yellow("⚠️ The stake stops earning rewards and can only be withdrawn after the exit window."))
+		fmt.Print(utils.Cyan("❓ Do you want to signal the unstake intent? [y/N]: "))
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
+			fmt.Println(utils.Red("❌ Unstake cancelled."))
+			return
+		}
+	}
+
+	unstakeTime, err := validator.UnstakeIntent(rpcProvider, options.Config.Wallet.Wallet)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error signaling the unstake intent: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Unstake intent confirmed, the stake can be withdrawn from %s", unstakeTime.Format(time.DateTime))))
+
+	// The validator has nothing to attest for anymore
+	if process.GetProcessInfo(string(types.ClientStarkValidator)) == nil {
+		return
+	}
+	fmt.Println(utils.Cyan("⏳ Stopping the validator client..."))
+	timeout := clients.StopTimeout(options.Config, types.ClientStarkValidator)
+	// Through the daemon when it supervises the validator, or it would be restarted
+	result, err := daemon.StopClient(options.Config, types.ClientStarkValidator)
+	if err != nil {
+		fmt.Printf(utils.Red("Could not stop validator process: %v\n"), err)
+		return
+	}
+	printStopResult("validator", result, timeout)
+}
+
+func validatorUnstakeStatusCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	validatorInfo, err := validator.GetValidatorInfo(rpcProvider, options.Config.Wallet.Wallet)
+	if err != nil {
+		fmt.Printf(utils.Red("❌ Error getting validator info: %v\n"), err)
+		return
+	}
+	if validatorInfo.UnstakeTime.IsZero() {
+		fmt.Println(utils.Yellow("🤔 No unstake intent, the stake is active."))
+		return
+	}
+
+	utils.PrintKV("Staked", fmt.Sprintf("%.4f STRK", validatorInfo.TotalStaked))
+	utils.PrintKV("Withdrawable from", validatorInfo.UnstakeTime.Format(time.DateTime))
+	remaining := time.Until(validatorInfo.UnstakeTime)
+	if remaining > 0 {
+		utils.PrintKV("Time left", utils.Yellow(remaining.Round(time.Second).String()))
+		return
+	}
+	utils.PrintKV("Time left", utils.Green("none, run `starknode-kit validator unstake action`"))
+}
+
+func validatorUnstakeActionCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	validatorInfo, err := validator.GetValidatorInfo(rpcProvider, options.Config.Wallet.Wallet)
+	if err != nil {
+		fmt.Printf(utils.Red("❌ Error getting validator info: %v\n"), err)
+		return
+	}
+
+	if err := validator.UnstakeAction(rpcProvider, options.Config.Wallet.Wallet); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error withdrawing the stake: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Withdrew %.4f STRK, the address is no longer a staker", validatorInfo.TotalStaked)))
+}
+
+// saveValidatorConfig saves a config changed after a confirmed transaction
+func saveValidatorConfig(cfg types.StarkNodeKitConfig) bool {
+	if err := utils.UpdateStarkNodeConfig(cfg); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ The change is on chain but failed to save config: %v", err)))
+		return false
+	}
+	options.Config = cfg
+	return true
+}
+
+func validatorSetRewardAddressCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	rewardAddress := args[0]
+	if err := validator.SetRewardAddress(rpcProvider, options.Config.Wallet.Wallet, rewardAddress); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error changing the reward address: %v", err)))
+		return
+	}
+
+	cfg := options.Config
+	cfg.Wallet.RewardAddress = rewardAddress
+	if saveValidatorConfig(cfg) {
+		fmt.Println(utils.Green(fmt.Sprintf("✅ Rewards are now paid to %s", rewardAddress)))
+	}
+}
+
+func validatorSetOperationalAddressCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	name := args[0]
+	operational, ok := wallet.Find(options.Config, name)
+	if !ok {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ No wallet named %s.", name)))
+		fmt.Println(utils.Yellow("💡 Import the operational account with `starknode-kit wallet import` first."))
+		return
+	}
+	if wallet.IsOperational(options.Config, operational) {
+		fmt.Println(utils.Yellow(fmt.Sprintf("🤔 %s already signs for the validator.", name)))
+		return
+	}
+
+	// Check the config change before anything is sent
+	cfg := options.Config
+	if err := wallet.UseOperational(&cfg, name); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	if err := validator.SetOperationalAddress(rpcProvider, options.Config.Wallet.Wallet, operational.Wallet); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error changing the operational address: %v", err)))
+		return
+	}
+	if !saveValidatorConfig(cfg) {
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ %s (%s) now signs for the validator", name, operational.Wallet.Address)))
+
+	if options.IsClientRunning(types.ClientStarkValidator) {
+		restartClient(types.ClientStarkValidator)
+	}
+}
+
+func validatorSetCommissionCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(args[0], "%"))
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Invalid commission %q, pass a whole percentage such as 10", args[0])))
+		return
+	}
+	if err := validator.SetCommission(rpcProvider, options.Config.Wallet.Wallet, percent); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error setting the commission: %v", err)))
+		return
+	}
+
+	cfg := options.Config
+	cfg.Wallet.StakeCommision = strconv.Itoa(percent)
+	if saveValidatorConfig(cfg) {
+		fmt.Println(utils.Green(fmt.Sprintf("✅ Commission set to %d%%", percent)))
+	}
+}
+
+func validatorPoolOpenCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	poolAddress, err := validator.OpenPool(rpcProvider, options.Config.Wallet.Wallet)
+	if errors.Is(err, validator.ErrNoCommission) {
+		fmt.Println(utils.Red("❌ Set the commission before opening the delegation pool."))
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit validator set commission <percent>`."))
+		return
+	}
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error opening the delegation pool: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Delegation pool open at %s", poolAddress)))
+	fmt.Println(utils.Yellow("💡 Delegators stake STRK to this address."))
+}
+
+func validatorPoolInfoCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	pool, err := validator.GetPoolInfo(rpcProvider, options.Config.Wallet.Wallet)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error getting pool info: %v", err)))
+		return
+	}
+	utils.PrintKV("Pool Address", pool.PoolAddress)
+	utils.PrintKV("Token", pool.TokenAddress)
+	utils.PrintKV("Total Delegated", fmt.Sprintf("%.4f STRK", pool.TotalDelegated))
+	utils.PrintKV("Commission", fmt.Sprintf("%.2f%%", pool.Commission))
+}
+
+func validatorPoolMembersCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	pool, err := validator.GetPoolInfo(rpcProvider, options.Config.Wallet.Wallet)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error getting pool info: %v", err)))
+		return
+	}
+	fmt.Println(utils.Cyan("⏳ Reading the pool's members..."))
+	members, err := validator.GetPoolMembers(rpcProvider, pool.PoolAddress)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Error listing pool members: %v", err)))
+		return
+	}
+	if len(members) == 0 {
+		fmt.Println(utils.Yellow("🤔 Nobody delegates to the pool yet."))
+		return
+	}
+	for _, member := range members {
+		utils.PrintKV(member.Address, fmt.Sprintf("%.4f STRK (unclaimed rewards %.4f STRK)", member.Amount, member.UnclaimedRewards))
+	}
+	fmt.Printf("\n%d members, %.4f STRK delegated\n", len(members), pool.TotalDelegated)
+}
+
 func validatorStatusCommandRun(cmd *cobra.Command, args []string) {
 	clientName := string(types.ClientStarkValidator)
-	processInfo := process.GetProcessInfo(clientName)
+	processInfo := process.GetProcessUsage(clientName)
 	if processInfo != nil {
 		fmt.Printf("Client: %s\n", utils.Blue(processInfo.Name))
 		fmt.Printf("  Status: %s (PID: %d)\n", utils.Green("Running"), processInfo.PID)
 		fmt.Printf("  Uptime: %s\n", utils.Green(processInfo.Uptime.Round(time.Second).String()))
+		displayResourceUsage(processInfo)
 	} else {
 		fmt.Printf("  Status: %s\n", utils.Red("Stopped"))
 	}
 
-	junoMetrics := utils.GetJunoMetrics(options.Config.Network)
+	junoMetrics := utils.GetJunoMetrics(options.Config.Network, options.Config.JunoConfig.Ports().HTTPURL())
 	fmt.Printf("\nJuno Node Status:\n")
 	if junoMetrics.IsSyncing {
 		fmt.Printf("  Sync Status: %s\n", utils.Yellow("Syncing"))
@@ -218,10 +648,28 @@ func validatorStatusCommandRun(cmd *cobra.Command, args []string) {
 
 func init() {
 	ValidatorCommand.Flags().BoolP("version", "v", false, "Get validator version")
-	ValidatorCommand.Flags().String("rpc", "", "Set juno RPC endpoint")
 	ValidatorCommand.AddCommand(validatorInfoCommand)
 	ValidatorCommand.AddCommand(validatorStatusCommand)
 	ValidatorCommand.AddCommand(validatorStopCommand)
 	ValidatorCommand.AddCommand(validatorStartCommand)
 	ValidatorCommand.AddCommand(validatorBalanceCommand)
+	addDryRunFlags(validatorStartCommand)
+	validatorClaimRewardsCommand.Flags().Bool("dry-run", false, "Only estimate the fee of the claim")
+	validatorClaimRewardsCommand.Flags().Bool("json", false, "Print the result as JSON")
+	ValidatorCommand.AddCommand(validatorClaimRewardsCommand)
+	validatorIncreaseStakeCommand.Flags().String("amount", "", "STRK to add to the stake, e.g. 1000 or 2.5")
+	ValidatorCommand.AddCommand(validatorIncreaseStakeCommand)
+	validatorUnstakeIntentCommand.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+	validatorUnstakeCommand.AddCommand(validatorUnstakeIntentCommand)
+	validatorUnstakeCommand.AddCommand(validatorUnstakeStatusCommand)
+	validatorUnstakeCommand.AddCommand(validatorUnstakeActionCommand)
+	ValidatorCommand.AddCommand(validatorUnstakeCommand)
+	validatorSetCommand.AddCommand(validatorSetRewardAddressCommand)
+	validatorSetCommand.AddCommand(validatorSetOperationalAddressCommand)
+	validatorSetCommand.AddCommand(validatorSetCommissionCommand)
+	ValidatorCommand.AddCommand(validatorSetCommand)
+	validatorPoolCommand.AddCommand(validatorPoolOpenCommand)
+	validatorPoolCommand.AddCommand(validatorPoolInfoCommand)
+	validatorPoolCommand.AddCommand(validatorPoolMembersCommand)
+	ValidatorCommand.AddCommand(validatorPoolCommand)
 }
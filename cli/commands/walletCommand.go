@@ -0,0 +1,498 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thebuidl-grid/starknode-kit/cli/options"
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/keystore"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+	"github.com/thebuidl-grid/starknode-kit/pkg/wallet"
+)
+
+var WalletCommand = &cobra.Command{
+	Use:   "wallet",
+	Short: "Manage Starknet wallets and their keystores",
+	Long: `Wallets are Starknet accounts whose private keys are kept in password protected
+keystores. The active wallet stakes; the operational wallet signs for the staking
+validator and can be a different one. Commands that sign transactions and the staking
+validator decrypt the key when they need it. Unlock the wallet to run several of them
+without typing the password each time.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var walletUnlockCommand = &cobra.Command{
+	Use:   "unlock",
+	Short: "Keep the wallet key unlocked for a while",
+	Run:   walletUnlockCommandRun,
+}
+
+var walletLockCommand = &cobra.Command{
+	Use:   "lock",
+	Short: "Forget the unlocked wallet key",
+	Run:   walletLockCommandRun,
+}
+
+var walletMigrateCommand = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move a plaintext private key from .starknode.env into a keystore",
+	Run:   walletMigrateCommandRun,
+}
+
+var walletImportCommand = &cobra.Command{
+	Use:   "import <name>",
+	Short: "Import an existing Argent, Braavos or OpenZeppelin account",
+	Long: `Imports a deployed account by address and private key, or from a keystore file
+exported by starknode-kit. The private key is read from the terminal without echo, or
+from stdin when it is piped. The account's class hash and public key are checked on
+chain before the wallet is saved.`,
+	Args: cobra.ExactArgs(1),
+	Run:  walletImportCommandRun,
+}
+
+var walletListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured wallets",
+	Run:   walletListCommandRun,
+}
+
+var walletShowCommand = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show the details of a wallet, the active one by default",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   walletShowCommandRun,
+}
+
+var walletExportCommand = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Export the keystore or the private key of a wallet",
+	Args:  cobra.ExactArgs(1),
+	Run:   walletExportCommandRun,
+}
+
+var walletRemoveCommand = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a wallet from the config",
+	Args:  cobra.ExactArgs(1),
+	Run:   walletRemoveCommandRun,
+}
+
+var walletUseCommand = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a wallet the active or the operational one",
+	Args:  cobra.ExactArgs(1),
+	Run:   walletUseCommandRun,
+}
+
+// walletKeystore returns the keystore of the configured wallet, printing why there is none
+func walletKeystore() (string, bool) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return "", false
+	}
+	path := options.Config.Wallet.Wallet.Keystore
+	if path != "" {
+		return path, true
+	}
+	fmt.Println(utils.Red("❌ The wallet has no keystore."))
+	if keystore.HasPlaintextKey(constants.EnvFIlePath) {
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit wallet migrate` to encrypt the key stored in .starknode.env."))
+	}
+	return "", false
+}
+
+func walletUnlockCommandRun(cmd *cobra.Command, args []string) {
+	path, ok := walletKeystore()
+	if !ok {
+		return
+	}
+	ttl, _ := cmd.Flags().GetDuration("timeout")
+
+	password, err := keystore.ReadPassword("Wallet password: ")
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	until, err := keystore.Unlock(path, password, ttl)
+	if errors.Is(err, keystore.ErrWrongPassword) {
+		fmt.Println(utils.Red("❌ Wrong password."))
+		return
+	}
+	if errors.Is(err, keystore.ErrNoRuntimeDir) {
+		fmt.Println(utils.Red("❌ There is no tmpfs runtime directory to keep the unlocked key in."))
+		fmt.Println(utils.Yellow("💡 Set XDG_RUNTIME_DIR, or let every command ask for the password."))
+		return
+	}
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to unlock the wallet: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Wallet unlocked until %s", until.Format(time.TimeOnly))))
+}
+
+func walletLockCommandRun(cmd *cobra.Command, args []string) {
+	path, ok := walletKeystore()
+	if !ok {
+		return
+	}
+	if err := keystore.Lock(path); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to lock the wallet: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green("✅ Wallet locked"))
+}
+
+func walletMigrateCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	if !keystore.HasPlaintextKey(constants.EnvFIlePath) {
+		fmt.Println(utils.Yellow("🤔 No plaintext private key found, nothing to migrate."))
+		return
+	}
+
+	name := options.Config.Wallet.Name
+	if name == "" {
+		name = "default"
+	}
+	path := keystore.Path(name)
+	fmt.Println(utils.Cyan("🔐 Choose a password to encrypt the wallet's private key."))
+	password, err := keystore.NewPassword()
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	if _, err := keystore.MigrateEnvFile(constants.EnvFIlePath, path, password); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Migration failed: %v", err)))
+		return
+	}
+
+	options.Config.Wallet.Wallet.Keystore = path
+	options.Config.Wallet.Wallet.PrivateKey = ""
+	if options.Config.IsValidatorNode {
+		options.Config.ValidatorConfig.SignerConfig.Keystore = path
+		options.Config.ValidatorConfig.SignerConfig.WalletPrivateKey = ""
+	}
+	if err := utils.UpdateStarkNodeConfig(options.Config); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Keystore written to %s but failed to save config: %v", path, err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Private key moved to %s and removed from .starknode.env", path)))
+}
+
+func walletImportCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	name := args[0]
+	if _, exists := wallet.Find(options.Config, name); exists {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ A wallet named %s already exists.", name)))
+		return
+	}
+	address, _ := cmd.Flags().GetString("address")
+	kind, _ := cmd.Flags().GetString("type")
+	keystoreFile, _ := cmd.Flags().GetString("keystore")
+	noVerify, _ := cmd.Flags().GetBool("no-verify")
+
+	var ks *keystore.Keystore
+	var privateKey string
+	var err error
+	if keystoreFile != "" {
+		ks, err = keystore.Load(keystoreFile)
+		if err == nil {
+			if address == "" {
+				address = ks.Address
+			}
+			var password string
+			if password, err = keystore.ReadPassword("Keystore password: "); err == nil {
+				privateKey, err = keystore.Decrypt(ks, password)
+			}
+		}
+	} else {
+		privateKey, err = keystore.ReadSecret("Private key: ")
+	}
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	if address == "" {
+		fmt.Println(utils.Red("❌ Pass the account address with --address."))
+		return
+	}
+	publicKey, err := wallet.PublicKey(privateKey)
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+
+	imported := types.Wallet{Address: address, PublicKey: publicKey, Deployed: true, Kind: kind}
+	if noVerify {
+		fmt.Println(utils.Yellow("⚠️ Skipping the on-chain check of the account."))
+	} else {
+		fmt.Println(utils.Cyan("⏳ Checking the account on chain..."))
+		provider, err := utils.CreateRPCProvider(options.Config.Network)
+		if err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ Error creating RPC provider: %v", err)))
+			return
+		}
+		account, err := wallet.Verify(cmd.Context(), provider, address, publicKey, kind)
+		if err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+		imported.Kind = account.Kind
+		imported.ClassHash = account.ClassHash
+		fmt.Println(utils.Green(fmt.Sprintf("✅ %s account with class hash %s", account.Kind, account.ClassHash)))
+	}
+
+	if ks == nil {
+		fmt.Println(utils.Cyan("🔐 Choose a password to encrypt the wallet's private key."))
+		password, err := keystore.NewPassword()
+		if err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+		if ks, err = keystore.Encrypt(imported, privateKey, password); err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+	}
+	ks.Address, ks.PublicKey = address, publicKey
+	imported.Keystore = keystore.Path(name)
+	if err := keystore.Save(imported.Keystore, ks); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+
+	if err := wallet.Add(&options.Config, types.WalletConfig{Name: name, Wallet: imported}); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	if err := utils.UpdateStarkNodeConfig(options.Config); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to save config: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Imported wallet %s (%s)", name, address)))
+	if !wallet.IsActive(options.Config, name) {
+		fmt.Println(utils.Yellow(fmt.Sprintf("💡 Run `starknode-kit wallet use %s` to stake with it, or add --operational to sign with it.", name)))
+	}
+}
+
+func walletListCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	wallets := wallet.All(options.Config)
+	if len(wallets) == 0 {
+		fmt.Println(utils.Yellow("🤔 No wallets configured."))
+		fmt.Println(utils.Yellow("💡 Run `starknode-kit wallet import` or `starknode-kit config new --validator`."))
+		return
+	}
+	for _, w := range wallets {
+		utils.PrintKV(w.Name, fmt.Sprintf("%s %s", w.Wallet.Address, walletRoles(w)))
+	}
+}
+
+func walletShowCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	name := options.Config.Wallet.Name
+	if len(args) > 0 {
+		name = args[0]
+	}
+	w, ok := wallet.Find(options.Config, name)
+	if !ok {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ No wallet named %s.", name)))
+		return
+	}
+
+	utils.PrintSection(w.Name)
+	utils.PrintKV("Address", w.Wallet.Address)
+	utils.PrintKV("Type", w.Wallet.Kind)
+	utils.PrintKV("Class hash", w.Wallet.ClassHash)
+	utils.PrintKV("Public key", w.Wallet.PublicKey)
+	utils.PrintKV("Roles", walletRoles(w))
+	utils.PrintKV("Reward address", w.RewardAddress)
+	utils.PrintKV("Commission", w.StakeCommision)
+	if w.Wallet.Keystore == "" {
+		utils.PrintKV("Keystore", utils.Red("none, the key is stored unencrypted"))
+		return
+	}
+	utils.PrintKV("Keystore", w.Wallet.Keystore)
+	if until, ok := keystore.UnlockedUntil(w.Wallet.Keystore); ok {
+		utils.PrintKV("Unlocked until", until.Format(time.TimeOnly))
+	} else {
+		utils.PrintKV("Unlocked", "no")
+	}
+}
+
+func walletExportCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	w, ok := wallet.Find(options.Config, args[0])
+	if !ok {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ No wallet named %s.", args[0])))
+		return
+	}
+	if w.Wallet.Keystore == "" {
+		fmt.Println(utils.Red("❌ The wallet has no keystore, run `starknode-kit wallet migrate` first."))
+		return
+	}
+	rawKey, _ := cmd.Flags().GetBool("private-key")
+	output, _ := cmd.Flags().GetString("output")
+
+	var data []byte
+	if rawKey {
+		// Always ask for the password, an unlocked session is not enough
+		ks, err := keystore.Load(w.Wallet.Keystore)
+		if err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+		password, err := keystore.ReadPassword("Wallet password: ")
+		if err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+		key, err := keystore.Decrypt(ks, password)
+		if err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+		data = []byte(key + "\n")
+	} else {
+		var err error
+		if data, err = os.ReadFile(w.Wallet.Keystore); err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+	}
+
+	if output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(output, data, 0o600); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Exported %s to %s", w.Name, output)))
+}
+
+func walletRemoveCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	removed, err := wallet.Remove(&options.Config, args[0])
+	if err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	if err := utils.UpdateStarkNodeConfig(options.Config); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to save config: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Removed wallet %s", removed.Name)))
+
+	deleteKeystore, _ := cmd.Flags().GetBool("delete-keystore")
+	if removed.Wallet.Keystore == "" {
+		return
+	}
+	if !deleteKeystore {
+		fmt.Println(utils.Yellow(fmt.Sprintf("💡 Its keystore is kept at %s", removed.Wallet.Keystore)))
+		return
+	}
+	if err := os.Remove(removed.Wallet.Keystore); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to delete the keystore: %v", err)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ Deleted %s", removed.Wallet.Keystore)))
+}
+
+func walletUseCommandRun(cmd *cobra.Command, args []string) {
+	if !options.LoadedConfig {
+		fmt.Println(utils.Red("❌ Config not found. Please run `starknode-kit config new`"))
+		return
+	}
+	name := args[0]
+	operational, _ := cmd.Flags().GetBool("operational")
+
+	if operational {
+		if !options.Config.IsValidatorNode {
+			fmt.Println(utils.Red("❌ This is not a validator node. Check your configuration."))
+			return
+		}
+		if err := wallet.UseOperational(&options.Config, name); err != nil {
+			fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+			return
+		}
+	} else if err := wallet.Use(&options.Config, name); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ %v", err)))
+		return
+	}
+	if err := utils.UpdateStarkNodeConfig(options.Config); err != nil {
+		fmt.Println(utils.Red(fmt.Sprintf("❌ Failed to save config: %v", err)))
+		return
+	}
+
+	if !operational {
+		fmt.Println(utils.Green(fmt.Sprintf("✅ %s is now the active wallet", name)))
+		return
+	}
+	fmt.Println(utils.Green(fmt.Sprintf("✅ %s now signs for the staking validator", name)))
+	if options.IsClientRunning(types.ClientStarkValidator) {
+		fmt.Println(utils.Yellow("💡 Restart the validator with `starknode-kit restart starknet-staking-v2` to use it."))
+	}
+}
+
+// walletRoles describes what a wallet is used for
+func walletRoles(w types.WalletConfig) string {
+	var roles []string
+	if wallet.IsActive(options.Config, w.Name) {
+		roles = append(roles, "active")
+	}
+	if wallet.IsOperational(options.Config, w) {
+		roles = append(roles, "operational")
+	}
+	if len(roles) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(roles, ", ") + ")"
+}
+
+func init() {
+	walletUnlockCommand.Flags().Duration("timeout", keystore.DefaultSessionTTL, "How long the key stays unlocked")
+	walletImportCommand.Flags().String("address", "", "Address of the deployed account")
+	walletImportCommand.Flags().String("type", "", fmt.Sprintf("Account type, one of %s (detected if not set)", strings.Join(wallet.Kinds, ", ")))
+	walletImportCommand.Flags().String("keystore", "", "Import a keystore file instead of a private key")
+	walletImportCommand.Flags().Bool("no-verify", false, "Don't check the account on chain")
+	walletExportCommand.Flags().Bool("private-key", false, "Export the decrypted private key instead of the keystore")
+	walletExportCommand.Flags().StringP("output", "o", "", "Write to a file with mode 0600 instead of stdout")
+	walletRemoveCommand.Flags().Bool("delete-keystore", false, "Also delete the wallet's keystore file")
+	walletUseCommand.Flags().Bool("operational", false, "Sign for the staking validator with this wallet instead of staking with it")
+	WalletCommand.AddCommand(walletImportCommand)
+	WalletCommand.AddCommand(walletListCommand)
+	WalletCommand.AddCommand(walletShowCommand)
+	WalletCommand.AddCommand(walletExportCommand)
+	WalletCommand.AddCommand(walletRemoveCommand)
+	WalletCommand.AddCommand(walletUseCommand)
+	WalletCommand.AddCommand(walletUnlockCommand)
+	WalletCommand.AddCommand(walletLockCommand)
+	WalletCommand.AddCommand(walletMigrateCommand)
+}
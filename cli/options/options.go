@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/thebuidl-grid/starknode-kit/pkg"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 )
@@ -33,7 +34,8 @@ func LoadLogs(clients []string) {
 
 	time.Sleep(3 * time.Second)
 
-	logs := []string{"-f"}
+	// -F follows the active segment across log rotations
+	logs := []string{"-F"}
 
 	for _, i := range clients {
 
@@ -63,10 +65,5 @@ func LoadLogs(clients []string) {
 }
 
 func IsClientRunning(client types.ClientType) bool {
-	for _, i := range utils.GetRunningClients() {
-		if i.Name == string(client) {
-			return true
-		}
-	}
-	return false
+	return process.GetProcessInfo(string(client)) != nil
 }
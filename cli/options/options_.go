@@ -2,11 +2,11 @@ package options
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"slices"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/logrotate"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 )
@@ -26,28 +26,5 @@ func getLatestLogFile(clientName string) (string, error) {
 
 	logDir = filepath.Join(baseDir, clientName, "logs")
 
-	files, err := os.ReadDir(logDir)
-	if err != nil {
-		return "", fmt.Errorf("could not read log directory %s: %w", logDir, err)
-	}
-
-	var newestFile os.FileInfo
-	for _, f := range files {
-		if f.IsDir() {
-			continue
-		}
-		fileInfo, err := f.Info()
-		if err != nil {
-			continue
-		}
-		if newestFile == nil || fileInfo.ModTime().After(newestFile.ModTime()) {
-			newestFile = fileInfo
-		}
-	}
-
-	if newestFile == nil {
-		return "", fmt.Errorf("no log files found in %s", logDir)
-	}
-
-	return filepath.Join(logDir, newestFile.Name()), nil
+	return logrotate.LatestLogFile(logDir, clientName)
 }
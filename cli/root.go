@@ -43,12 +43,19 @@ func init() {
 	rootCmd.AddCommand(commands.VersionCommand)
 	rootCmd.AddCommand(commands.MonitorCmd)
 	rootCmd.AddCommand(commands.StopCommand)
+	rootCmd.AddCommand(commands.RestartCommand)
 	rootCmd.AddCommand(commands.AddCommand)
 	rootCmd.AddCommand(commands.StartCommand)
 	rootCmd.AddCommand(commands.RemoveCommand)
 	rootCmd.AddCommand(commands.RunCmd)
 	rootCmd.AddCommand(commands.UpdateCommand)
 	rootCmd.AddCommand(commands.ValidatorCommand)
+	rootCmd.AddCommand(commands.WalletCommand)
 	rootCmd.AddCommand(commands.StatusCommand)
+	rootCmd.AddCommand(commands.SuperviseCommand)
+	rootCmd.AddCommand(commands.ServiceCommand)
+	rootCmd.AddCommand(commands.DaemonCommand)
+	rootCmd.AddCommand(commands.LogWriterCommand)
+	rootCmd.AddCommand(commands.ClientFilesCommand)
 	rootCmd.AddCommand(configcommand.ConfigCommand)
 }
@@ -87,9 +87,6 @@ wallet:
   commision: ""
 
 validator_config:
-  provider_config:
-    juno_rpc_http: "http://localhost:6060"
-    juno_rpc_ws: "ws://localhost:6060"
   signer:
     operational_address: ""
     privateKey: ""`} />
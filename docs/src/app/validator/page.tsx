@@ -46,15 +46,11 @@ export default function Validator() {
 
       <CodeBlock code="starknode-kit validator --version" />
 
-      <h3 className="text-2xl font-semibold mt-10 mb-5">Set Juno RPC Endpoint</h3>
+      <h3 className="text-2xl font-semibold mt-10 mb-5">Juno Endpoint</h3>
 
-      <p>Configure the Juno RPC endpoint for your validator:</p>
+      <p>The validator connects to the HTTP and WebSocket ports of the configured Juno node. Change them with:</p>
 
-      <CodeBlock code="starknode-kit validator --rpc http://localhost:6060" />
-
-      <p>Or use a remote Juno node:</p>
-
-      <CodeBlock code="starknode-kit validator --rpc https://your-juno-node.example.com" />
+      <CodeBlock code="starknode-kit config set starknet http_port=16060 ws_port=16061" />
 
       <h2 className="text-3xl font-semibold mt-16 mb-6">Configuration</h2>
 
@@ -76,9 +72,6 @@ wallet:
     legacy: false
 
 validator_config:
-  provider_config:
-    juno_rpc_http: "http://localhost:6060"
-    juno_rpc_ws: "ws://localhost:6060"
   signer:
     operational_address: "0x..."
     privateKey: "\${STARKNET_PRIVATE_KEY}"`} />
@@ -0,0 +1,57 @@
+package clients
+
+import "strings"
+
+// mergeArgs appends the user's extra arguments to the generated ones. A user
+// flag replaces every generated occurrence of the same flag, whether it was
+// written as --flag=value or --flag value.
+func mergeArgs(generated, extra []string) []string {
+	if len(extra) == 0 {
+		return generated
+	}
+
+	overridden := make(map[string]bool)
+	for _, group := range flagGroups(extra) {
+		if name := flagName(group[0]); name != "" {
+			overridden[name] = true
+		}
+	}
+
+	merged := make([]string, 0, len(generated)+len(extra))
+	for _, group := range flagGroups(generated) {
+		if overridden[flagName(group[0])] {
+			continue
+		}
+		merged = append(merged, group...)
+	}
+	return append(merged, extra...)
+}
+
+// flagGroups splits arguments into a flag followed by its separate values.
+// Leading positional arguments such as subcommands form their own groups.
+func flagGroups(args []string) [][]string {
+	var groups [][]string
+	for _, arg := range args {
+		if len(groups) == 0 || isFlag(arg) || !isFlag(groups[len(groups)-1][0]) {
+			groups = append(groups, []string{arg})
+			continue
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], arg)
+	}
+	return groups
+}
+
+// flagName returns the name of a flag without dashes or value, or "" for a
+// positional argument
+func flagName(arg string) string {
+	if !isFlag(arg) {
+		return ""
+	}
+	name := strings.TrimLeft(arg, "-")
+	name, _, _ = strings.Cut(name, "=")
+	return name
+}
+
+func isFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-") && len(arg) > 1
+}
@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestMergeArgs(t *testing.T) {
+	generated := []string{"bn", "--network", "mainnet", "--http", "--metrics-port", "5054", "--port=9000"}
+
+	tests := []struct {
+		name     string
+		extra    []string
+		expected []string
+	}{
+		{"no extra args", nil, generated},
+		{
+			"new flags are appended",
+			[]string{"--graffiti", "my node"},
+			[]string{"bn", "--network", "mainnet", "--http", "--metrics-port", "5054", "--port=9000", "--graffiti", "my node"},
+		},
+		{
+			"separate value overridden with equals form",
+			[]string{"--metrics-port=6000"},
+			[]string{"bn", "--network", "mainnet", "--http", "--port=9000", "--metrics-port=6000"},
+		},
+		{
+			"equals form overridden with separate value",
+			[]string{"--port", "9100", "--http=false"},
+			[]string{"bn", "--network", "mainnet", "--metrics-port", "5054", "--port", "9100", "--http=false"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeArgs(generated, tt.extra); !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSpecIncludesExtraArgsAndEnv(t *testing.T) {
+	client, err := NewExecutionClient(types.ClientConfig{
+		Name:      types.ClientGeth,
+		Port:      []int{30303},
+		ExtraArgs: []string{"--cache=4096", "--http.port", "18545"},
+		Env:       []string{"GOMEMLIMIT=8GiB"},
+	}, "mainnet")
+	if err != nil {
+		t.Fatalf("NewExecutionClient failed: %v", err)
+	}
+
+	spec := client.Spec()
+	if slices.Contains(spec.Args, "--http.port=8545") {
+		t.Errorf("expected the generated --http.port to be overridden, got %v", spec.Args)
+	}
+	if !slices.Equal(spec.Args[len(spec.Args)-3:], []string{"--cache=4096", "--http.port", "18545"}) {
+		t.Errorf("expected extra args last, got %v", spec.Args)
+	}
+	if !slices.Equal(spec.Env, []string{"GOMEMLIMIT=8GiB"}) {
+		t.Errorf("expected env to be passed through, got %v", spec.Env)
+	}
+}
@@ -1,7 +1,10 @@
 package clients
 
 import (
+	"encoding/json"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
@@ -11,6 +14,7 @@ import (
 func TestGethClient(t *testing.T) {
 	config := &gethConfig{
 		port:          30303,
+		ports:         types.DefaultPorts(types.ClientGeth),
 		executionType: "full",
 		network:       "mainnet",
 	}
@@ -53,6 +57,7 @@ func TestGethClient(t *testing.T) {
 func TestRethClient(t *testing.T) {
 	config := &rethConfig{
 		port:          30303,
+		ports:         types.DefaultPorts(types.ClientReth),
 		executionType: "full",
 		network:       "mainnet",
 	}
@@ -91,6 +96,8 @@ func TestRethClient(t *testing.T) {
 func TestLighthouseClient(t *testing.T) {
 	config := &lightHouseConfig{
 		port:                []int{9000, 9001},
+		ports:               types.DefaultPorts(types.ClientLighthouse),
+		engineURL:           "http://localhost:8551",
 		consensusCheckpoint: "https://checkpoint.sync",
 		network:             "mainnet",
 	}
@@ -118,6 +125,10 @@ func TestLighthouseClient(t *testing.T) {
 		"--metrics-port",
 		"5054",
 		"--http",
+		"--http-address",
+		"127.0.0.1",
+		"--http-port",
+		"5052",
 		"--disable-upnp",
 		"--datadir=" + filepath.Join(constants.InstallClientsDir, "lighthouse", "database"),
 	}
@@ -138,6 +149,8 @@ func TestLighthouseClient(t *testing.T) {
 func TestPrysmClient(t *testing.T) {
 	config := &prysmConfig{
 		port:                []int{9000, 9001},
+		ports:               types.DefaultPorts(types.ClientPrysm),
+		engineURL:           "http://localhost:8551",
 		consensusCheckpoint: "https://checkpoint.sync",
 		network:             "mainnet",
 	}
@@ -154,6 +167,7 @@ func TestPrysmClient(t *testing.T) {
 		"http://localhost:8551",
 		"--grpc-gateway-host=0.0.0.0",
 		"--grpc-gateway-port=5052",
+		"--rpc-port=4000",
 		"--checkpoint-sync-url=https://checkpoint.sync",
 		"--genesis-beacon-api-url=https://checkpoint.sync",
 		"--accept-terms-of-use=true",
@@ -214,11 +228,9 @@ func TestJunoClient(t *testing.T) {
 }
 
 func TestStarknetValidatorClient(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
 	config := &StakingValidator{
-		Provider: stakingValidatorProviderConfig{
-			starknetHttp: "http://localhost:6060",
-			starkentWS:   "ws://localhost:6061",
-		},
+		juno: types.PortsConfig{HTTP: 6060, WS: 6061},
 		Wallet: stakingValidatorWalletConfig{
 			address:    "0x123",
 			privatekey: "0x456",
@@ -228,10 +240,10 @@ func TestStarknetValidatorClient(t *testing.T) {
 	args := config.buildArgs()
 
 	expectedArgs := []string{
+		"--config", "/run/user/1000/starknode-kit/starknet-staking-v2.json",
 		"--provider-http", "http://localhost:6060",
 		"--provider-ws", "ws://localhost:6061",
 		"--signer-op-address", "0x123",
-		"--signer-priv-key", "0x456",
 	}
 
 	if len(args) != len(expectedArgs) {
@@ -246,3 +258,31 @@ func TestStarknetValidatorClient(t *testing.T) {
 
 	
 }
+
+func TestStarknetValidatorKeyInConfigFile(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	client := &StakingValidator{Wallet: stakingValidatorWalletConfig{address: "0x123", privatekey: "0x456"}}
+
+	spec := client.Spec()
+	if slices.Contains(spec.Args, "0x456") {
+		t.Fatalf("expected the private key to stay off the command line, got %v", spec.Args)
+	}
+	if len(spec.Files) != 1 || spec.Files[0].Path != flagValue(spec.Args, "config") {
+		t.Fatalf("expected the config file passed with --config to be written, got %+v", spec.Files)
+	}
+	if !strings.HasPrefix(spec.Files[0].Path, runtimeDir+string(filepath.Separator)) {
+		t.Errorf("expected the config file in the runtime directory, got %s", spec.Files[0].Path)
+	}
+	data, err := spec.Files[0].Data()
+	if err != nil {
+		t.Fatalf("failed to render the config file: %v", err)
+	}
+	var file validatorConfigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("invalid config file: %v", err)
+	}
+	if file.Signer.PrivateKey != "0x456" || file.Signer.OperationalAddress != "0x123" {
+		t.Errorf("unexpected signer config %+v", file.Signer)
+	}
+}
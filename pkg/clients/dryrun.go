@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"os"
+	"strings"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/logrotate"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// redacted replaces secret values in launch plans
+const redacted = "<redacted>"
+
+// dataDirFlags is the flag holding each client's database directory
+var dataDirFlags = map[types.ClientType]string{
+	types.ClientGeth:       "datadir",
+	types.ClientReth:       "datadir",
+	types.ClientLighthouse: "datadir",
+	types.ClientPrysm:      "datadir",
+	types.ClientJuno:       "db-path",
+}
+
+// secretMarkers identify flags and environment variables holding secrets
+var secretMarkers = []string{"priv-key", "private-key", "private_key", "password", "token"}
+
+// LaunchPlan describes exactly how a client would be launched, with secrets redacted
+type LaunchPlan struct {
+	Client  string   `json:"client"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Env     []string `json:"env"` // added to the inherited environment
+	WorkDir string   `json:"work_dir"`
+	LogFile string   `json:"log_file"`
+	DataDir string   `json:"data_dir,omitempty"`
+	Files   []string `json:"files,omitempty"` // written before launch and removed once read, contents are not shown
+}
+
+// Plan resolves the launch plan of a client without starting it
+func Plan(c types.IClient) LaunchPlan {
+	spec := c.Spec()
+	// Clients inherit the working directory of starknode-kit
+	workDir, _ := os.Getwd()
+	plan := LaunchPlan{
+		Client:  spec.Name,
+		Command: spec.Command,
+		Args:    redactArgs(spec.Args),
+		Env:     redactEnv(spec.Env),
+		WorkDir: workDir,
+		LogFile: logrotate.ActivePath(spec.LogDir, spec.Name),
+	}
+	for _, file := range spec.Files {
+		plan.Files = append(plan.Files, file.Path)
+	}
+	if flag, ok := dataDirFlags[types.ClientType(spec.Name)]; ok {
+		plan.DataDir = flagValue(spec.Args, flag)
+	}
+	return plan
+}
+
+// flagValue returns the value of the last occurrence of a flag, in either
+// --flag=value or --flag value form
+func flagValue(args []string, name string) string {
+	var value string
+	for _, group := range flagGroups(args) {
+		if flagName(group[0]) != name {
+			continue
+		}
+		if _, v, ok := strings.Cut(group[0], "="); ok {
+			value = v
+		} else if len(group) > 1 {
+			value = group[1]
+		}
+	}
+	return value
+}
+
+func redactArgs(args []string) []string {
+	var out []string
+	for _, group := range flagGroups(args) {
+		if !isSecret(flagName(group[0])) {
+			out = append(out, group...)
+			continue
+		}
+		if name, _, ok := strings.Cut(group[0], "="); ok {
+			out = append(out, name+"="+redacted)
+			continue
+		}
+		out = append(out, group[0])
+		for range group[1:] {
+			out = append(out, redacted)
+		}
+	}
+	return out
+}
+
+func redactEnv(env []string) []string {
+	out := make([]string, 0, len(env))
+	for _, pair := range env {
+		if key, _, ok := strings.Cut(pair, "="); ok && isSecret(key) {
+			pair = key + "=" + redacted
+		}
+		out = append(out, pair)
+	}
+	return out
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range secretMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package clients
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestPlanRedactsSecrets(t *testing.T) {
+	var cfg types.ValidatorConfig
+	cfg.SignerConfig.OperationalAddress = "0x123"
+	cfg.SignerConfig.WalletPrivateKey = "0xsecret"
+	cfg.Env = []string{"SIGNER_PRIVATE_KEY=0xsecret", "LOG_LEVEL=debug"}
+	client, err := NewValidatorClient(cfg, types.JunoConfig{})
+	if err != nil {
+		t.Fatalf("NewValidatorClient failed: %v", err)
+	}
+
+	plan := Plan(client)
+	if joined := strings.Join(append(plan.Args, plan.Env...), " "); strings.Contains(joined, "0xsecret") {
+		t.Fatalf("expected the private key to be redacted, got %s", joined)
+	}
+	if !slices.Contains(plan.Files, flagValue(plan.Args, "config")) {
+		t.Errorf("expected the validator config file to be listed, got %v", plan.Files)
+	}
+
+	// Secrets passed as extra flags are redacted too
+	plan.Args = redactArgs([]string{"--signer-priv-key", "0xsecret", "--signer-op-address", "0x123"})
+	if i := slices.Index(plan.Args, "--signer-priv-key"); i < 0 || plan.Args[i+1] != redacted {
+		t.Errorf("expected --signer-priv-key to keep its position with a redacted value, got %v", plan.Args)
+	}
+	if !slices.Contains(plan.Args, "0x123") || !slices.Contains(plan.Env, "LOG_LEVEL=debug") {
+		t.Errorf("expected other values to be kept, got %v %v", plan.Args, plan.Env)
+	}
+}
+
+func TestPlanPaths(t *testing.T) {
+	client, err := NewExecutionClient(types.ClientConfig{Name: types.ClientGeth, Port: []int{30303}}, "mainnet")
+	if err != nil {
+		t.Fatalf("NewExecutionClient failed: %v", err)
+	}
+	plan := Plan(client)
+	if plan.DataDir != filepath.Join(constants.InstallClientsDir, "geth", "database") {
+		t.Errorf("unexpected data dir %s", plan.DataDir)
+	}
+	if plan.LogFile != filepath.Join(constants.InstallClientsDir, "geth", "logs", "geth.log") {
+		t.Errorf("unexpected log file %s", plan.LogFile)
+	}
+
+	// A data dir from extra_args wins
+	client, _ = NewExecutionClient(types.ClientConfig{Name: types.ClientGeth, Port: []int{30303}, ExtraArgs: []string{"--datadir", "/data/geth"}}, "mainnet")
+	if plan := Plan(client); plan.DataDir != "/data/geth" {
+		t.Errorf("expected the overridden data dir, got %s", plan.DataDir)
+	}
+}
@@ -2,20 +2,40 @@ package clients
 
 import (
 	"fmt"
-	"strings"
-	"time"
 
-	"github.com/thebuidl-grid/starknode-kit/pkg/process"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
-	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 )
 
-func NewConsensusClient(cfg types.ClientConfig, network string) (types.IClient, error) {
+// NewConsensusClient builds a consensus client that talks to the execution
+// client's engine API at engineURL
+func NewConsensusClient(cfg types.ClientConfig, network, engineURL string) (types.IClient, error) {
 	switch cfg.Name {
 	case "lighthouse":
-		return &lightHouseConfig{consensusCheckpoint: cfg.ConsensusCheckpoint, port: cfg.Port, network: network}, nil
+		return &lightHouseConfig{
+			consensusCheckpoint: cfg.ConsensusCheckpoint,
+			port:                cfg.Port,
+			ports:               cfg.Ports(),
+			engineURL:           engineURL,
+			network:             network,
+			stopTimeout:         stopTimeoutOrDefault(cfg.StopTimeout, cfg.Name),
+			logs:                cfg.Logs,
+			extraArgs:           cfg.ExtraArgs,
+			env:                 cfg.Env,
+			resources:           cfg.Resources,
+		}, nil
 	case "prysm":
-		return &prysmConfig{consensusCheckpoint: cfg.ConsensusCheckpoint, port: cfg.Port, network: network}, nil
+		return &prysmConfig{
+			consensusCheckpoint: cfg.ConsensusCheckpoint,
+			port:                cfg.Port,
+			ports:               cfg.Ports(),
+			engineURL:           engineURL,
+			network:             network,
+			stopTimeout:         stopTimeoutOrDefault(cfg.StopTimeout, cfg.Name),
+			logs:                cfg.Logs,
+			extraArgs:           cfg.ExtraArgs,
+			env:                 cfg.Env,
+			resources:           cfg.Resources,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported consensus client: %s", cfg.Name)
 	}
@@ -24,9 +44,29 @@ func NewConsensusClient(cfg types.ClientConfig, network string) (types.IClient,
 func NewExecutionClient(cfg types.ClientConfig, network string) (types.IClient, error) {
 	switch cfg.Name {
 	case "geth":
-		return &gethConfig{executionType: cfg.ExecutionType, port: cfg.Port[0], network: network}, nil
+		return &gethConfig{
+			executionType: cfg.ExecutionType,
+			port:          cfg.Port[0],
+			ports:         cfg.Ports(),
+			network:       network,
+			stopTimeout:   stopTimeoutOrDefault(cfg.StopTimeout, cfg.Name),
+			logs:          cfg.Logs,
+			extraArgs:     cfg.ExtraArgs,
+			env:           cfg.Env,
+			resources:     cfg.Resources,
+		}, nil
 	case "reth":
-		return &rethConfig{executionType: cfg.ExecutionType, port: cfg.Port[0], network: network}, nil
+		return &rethConfig{
+			executionType: cfg.ExecutionType,
+			port:          cfg.Port[0],
+			ports:         cfg.Ports(),
+			network:       network,
+			stopTimeout:   stopTimeoutOrDefault(cfg.StopTimeout, cfg.Name),
+			logs:          cfg.Logs,
+			extraArgs:     cfg.ExtraArgs,
+			env:           cfg.Env,
+			resources:     cfg.Resources,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported execution client: %s", cfg.Name)
 	}
@@ -44,56 +84,73 @@ func NewJunoClient(config types.JunoConfig, network string, isvalidator bool) (t
 		config:          config,
 		network:         network,
 		isValidatorNode: isvalidator,
+		stopTimeout:     stopTimeoutOrDefault(config.StopTimeout, types.ClientJuno),
 	}, nil
 }
 
-func NewValidatorClient(config types.ValidatorConfig) (types.IClient, error) {
-
+// NewValidatorClient builds the staking validator, which uses the endpoints of
+// the configured Juno node
+func NewValidatorClient(config types.ValidatorConfig, juno types.JunoConfig) (types.IClient, error) {
 	return &StakingValidator{
-		Provider: stakingValidatorProviderConfig{
-			starknetHttp: config.ProviderConfig.JunoRPC,
-			starkentWS:   config.ProviderConfig.JunoWS,
-		},
+		juno: juno.Ports(),
 		Wallet: stakingValidatorWalletConfig{
 			address:    config.SignerConfig.OperationalAddress,
 			privatekey: config.SignerConfig.WalletPrivateKey,
+			keystore:   config.SignerConfig.Keystore,
 		},
+		stopTimeout: stopTimeoutOrDefault(config.StopTimeout, types.ClientStarkValidator),
+		logs:        config.Logs,
+		extraArgs:   config.ExtraArgs,
+		env:         config.Env,
+		resources:   config.Resources,
 	}, nil
 }
-func RestartClient(pid int) error {
-	// First stop the client
-	if err := process.StopClient(pid); err != nil {
-		// If it wasn't running, that's ok
-		if !strings.Contains(err.Error(), "not running") {
-			return err
-		}
-	}
 
-	// Wait a bit for clean shutdown
-	time.Sleep(2 * time.Second)
+// NewConfiguredClients builds every client enabled in the config, in start order:
+// execution, consensus, Juno and finally the staking validator
+func NewConfiguredClients(cfg types.StarkNodeKitConfig) ([]types.IClient, error) {
+	var configured []types.IClient
 
-	// Load config to get client settings
-	config, err := utils.LoadConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	if cfg.ExecutionCientSettings.Name != "" {
+		e, err := NewExecutionClient(cfg.ExecutionCientSettings, cfg.Network)
+		if err != nil {
+			return nil, err
+		}
+		configured = append(configured, e)
 	}
 
-	e, _ := NewExecutionClient(config.ExecutionCientSettings, config.Network)
-	err = e.Start()
-	if err != nil {
-		return err
+	if cfg.ConsensusCientSettings.Name != "" {
+		c, err := NewConsensusClient(cfg.ConsensusCientSettings, cfg.Network, EngineURL(cfg))
+		if err != nil {
+			return nil, err
+		}
+		configured = append(configured, c)
 	}
-	c, _ := NewConsensusClient(config.ConsensusCientSettings, config.Network)
-	err = c.Start()
-	if err != nil {
-		return err
+
+	if cfg.JunoConfig.EthNode != "" {
+		j, err := NewJunoClient(cfg.JunoConfig, cfg.Network, cfg.IsValidatorNode)
+		if err != nil {
+			return nil, err
+		}
+		configured = append(configured, j)
 	}
 
-	j, _ := NewJunoClient(config.JunoConfig, config.Network, config.IsValidatorNode)
-	err = j.Start()
-	if err != nil {
-		return err
+	if cfg.IsValidatorNode {
+		v, err := NewValidatorClient(cfg.ValidatorConfig, cfg.JunoConfig)
+		if err != nil {
+			return nil, err
+		}
+		configured = append(configured, v)
 	}
 
-	return nil
+	return configured, nil
+}
+
+// EngineURL returns the engine API endpoint of the configured execution client
+func EngineURL(cfg types.StarkNodeKitConfig) string {
+	el := cfg.ExecutionCientSettings.Name
+	if el == "" {
+		el = types.ClientGeth
+	}
+	return cfg.Ports(el).AuthRPCURL()
 }
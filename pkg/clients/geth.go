@@ -2,20 +2,26 @@ package clients
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"runtime"
 	"time"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 )
 
 // Configuration options for Geth
 type gethConfig struct {
 	port          int
+	ports         types.PortsConfig
 	executionType string
 	network       string
+	stopTimeout   time.Duration
+	logs          types.LogConfig
+	extraArgs     []string
+	env           []string
+	resources     types.ResourcesConfig
 }
 
 // GetGethCommand returns the geth command path based on platform
@@ -36,15 +42,15 @@ func (c *gethConfig) buildArgs() []string {
 		"--http",
 		"--http.api=eth,net,engine,admin",
 		"--http.corsdomain=*",
-		"--http.addr=0.0.0.0",
-		"--http.port=8545",
+		"--http.addr=" + c.ports.BindAddress,
+		fmt.Sprintf("--http.port=%d", c.ports.HTTP),
 		"--authrpc.jwtsecret=" + constants.JWTPath,
-		"--authrpc.addr=0.0.0.0",
-		"--authrpc.port=8551",
+		"--authrpc.addr=" + c.ports.BindAddress,
+		fmt.Sprintf("--authrpc.port=%d", c.ports.AuthRPC),
 		"--authrpc.vhosts=*",
 		"--metrics",
-		"--metrics.addr=0.0.0.0",
-		"--metrics.port=7878",
+		"--metrics.addr=" + c.ports.MetricsAddress,
+		fmt.Sprintf("--metrics.port=%d", c.ports.Metrics),
 	}
 
 	// Add execution type specific arguments
@@ -61,19 +67,20 @@ func (c *gethConfig) buildArgs() []string {
 	return args
 }
 
-func (c *gethConfig) Start() error {
-	args := c.buildArgs()
-	command := c.getCommand()
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	logFilePath := filepath.Join(
-		constants.InstallClientsDir,
-		"geth",
-		"logs",
-		fmt.Sprintf("geth_%s.log", timestamp))
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
+// Spec returns the resolved command used to launch the client
+func (c *gethConfig) Spec() types.ClientSpec {
+	return types.ClientSpec{
+		Name:        string(types.ClientGeth),
+		Command:     c.getCommand(),
+		Args:        mergeArgs(c.buildArgs(), c.extraArgs),
+		Env:         c.env,
+		LogDir:      filepath.Join(constants.InstallClientsDir, "geth", "logs"),
+		Logs:        c.logs,
+		StopTimeout: c.stopTimeout,
+		Resources:   c.resources,
 	}
+}
 
-	return process.StartClient("geth", command, logFile, args...)
+func (c *gethConfig) Start() error {
+	return process.StartClient(c.Spec())
 }
@@ -0,0 +1,107 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/versions"
+)
+
+// HookEvent is a lifecycle event hooks can be attached to
+type HookEvent string
+
+const (
+	HookPreStart   HookEvent = "pre_start"
+	HookPostStart  HookEvent = "post_start"
+	HookPreStop    HookEvent = "pre_stop"
+	HookPostStop   HookEvent = "post_stop"
+	HookPreUpdate  HookEvent = "pre_update"
+	HookPostUpdate HookEvent = "post_update"
+)
+
+// defaultHookTimeout is how long a hook may run when no timeout is configured
+const defaultHookTimeout = 5 * time.Minute
+
+// Hooks returns the configured hooks of a client
+func Hooks(cfg types.StarkNodeKitConfig, client types.ClientType) types.HooksConfig {
+	switch client {
+	case cfg.ExecutionCientSettings.Name:
+		return cfg.ExecutionCientSettings.Hooks
+	case cfg.ConsensusCientSettings.Name:
+		return cfg.ConsensusCientSettings.Hooks
+	case types.ClientJuno:
+		return cfg.JunoConfig.Hooks
+	case types.ClientStarkValidator:
+		return cfg.ValidatorConfig.Hooks
+	default:
+		return types.HooksConfig{}
+	}
+}
+
+func hookCommand(hooks types.HooksConfig, event HookEvent) string {
+	switch event {
+	case HookPreStart:
+		return hooks.PreStart
+	case HookPostStart:
+		return hooks.PostStart
+	case HookPreStop:
+		return hooks.PreStop
+	case HookPostStop:
+		return hooks.PostStop
+	case HookPreUpdate:
+		return hooks.PreUpdate
+	case HookPostUpdate:
+		return hooks.PostUpdate
+	default:
+		return ""
+	}
+}
+
+// RunHook runs the hook of a client for an event with /bin/sh, if one is
+// configured. The hook gets STARKNODE_CLIENT, STARKNODE_EVENT, STARKNODE_PID
+// (0 if the client is not running), STARKNODE_VERSION and STARKNODE_NETWORK in
+// its environment and is killed once its timeout expires.
+func RunHook(cfg types.StarkNodeKitConfig, client types.ClientType, event HookEvent, pid int) error {
+	hooks := Hooks(cfg, client)
+	command := hookCommand(hooks, event)
+	if command == "" {
+		return nil
+	}
+	timeout := hooks.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"STARKNODE_CLIENT="+string(client),
+		"STARKNODE_EVENT="+string(event),
+		"STARKNODE_PID="+strconv.Itoa(pid),
+		"STARKNODE_VERSION="+versions.GetVersionNumber(string(client)),
+		"STARKNODE_NETWORK="+cfg.Network,
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Kill everything the hook started, not just the shell
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	cmd.WaitDelay = 5 * time.Second
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s hook of %s timed out after %s", event, client, timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s hook of %s failed: %w", event, client, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func hooksConfig(hooks types.HooksConfig) types.StarkNodeKitConfig {
+	return types.StarkNodeKitConfig{
+		Network:                "sepolia",
+		ExecutionCientSettings: types.ClientConfig{Name: types.ClientGeth, Port: []int{30303}, Hooks: hooks},
+	}
+}
+
+func TestRunHookEnvironment(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+	cfg := hooksConfig(types.HooksConfig{
+		PostStop: `echo "$STARKNODE_CLIENT $STARKNODE_EVENT $STARKNODE_PID $STARKNODE_NETWORK" > ` + out,
+	})
+
+	if err := RunHook(cfg, types.ClientGeth, HookPostStop, 4242); err != nil {
+		t.Fatalf("RunHook failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected the hook to run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "geth post_stop 4242 sepolia" {
+		t.Errorf("unexpected hook environment %q", got)
+	}
+
+	// Events without a hook are a no-op
+	if err := RunHook(cfg, types.ClientGeth, HookPreStart, 0); err != nil {
+		t.Errorf("expected a missing hook to be skipped, got %v", err)
+	}
+}
+
+func TestRunHookTimeout(t *testing.T) {
+	cfg := hooksConfig(types.HooksConfig{PreUpdate: "sleep 30", Timeout: 200 * time.Millisecond})
+
+	start := time.Now()
+	err := RunHook(cfg, types.ClientGeth, HookPreUpdate, 0)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 200ms") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the hook to be killed, took %s", elapsed)
+	}
+}
+
+func TestFailingPreStartHookAbortsStart(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	cfg := hooksConfig(types.HooksConfig{PreStart: "echo disk not mounted >&2; exit 1"})
+
+	_, err := StartClient(cfg, types.ClientGeth)
+	if err == nil || !strings.Contains(err.Error(), "pre_start hook of geth failed") {
+		t.Fatalf("expected the failing hook to abort the start, got %v", err)
+	}
+	if record, _ := process.LoadRecord(string(types.ClientGeth)); record != nil {
+		t.Errorf("expected geth not to be started, got %+v", record)
+	}
+}
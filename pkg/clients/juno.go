@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
@@ -16,6 +17,7 @@ type JunoClient struct {
 	config          types.JunoConfig
 	isValidatorNode bool
 	network         string
+	stopTimeout     time.Duration
 }
 
 // getJunoPath returns the path to the Juno binary
@@ -31,34 +33,37 @@ func getJunoPath() string {
 	return ""
 }
 
-// StartNode starts a local Juno node
-func (c *JunoClient) Start() error {
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	logFilePath := filepath.Join(
-		constants.InstallStarknetDir,
-		"juno",
-		"logs",
-		fmt.Sprintf("juno_%s.log", timestamp))
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to create log file: %w", err)
+// Spec returns the resolved command used to launch Juno
+func (c *JunoClient) Spec() types.ClientSpec {
+	return types.ClientSpec{
+		Name:        string(types.ClientJuno),
+		Command:     getJunoPath(),
+		Args:        mergeArgs(c.buildJunoArgs(), c.config.ExtraArgs),
+		Env:         slices.Concat(c.config.Environment, c.config.Env),
+		LogDir:      filepath.Join(constants.InstallStarknetDir, "juno", "logs"),
+		Logs:        c.config.Logs,
+		StopTimeout: c.stopTimeout,
+		Resources:   c.config.Resources,
 	}
+}
 
-	args := c.buildJunoArgs()
-	return process.StartClient("juno", getJunoPath(), logFile, args...)
+// StartNode starts a local Juno node
+func (c *JunoClient) Start() error {
+	return process.StartClient(c.Spec())
 }
 
 // buildJunoArgs builds the command line arguments for Juno
 func (c *JunoClient) buildJunoArgs() []string {
+	ports := c.config.Ports()
 	args := []string{
 		"--http",
-		fmt.Sprintf("--http-port=%d", c.config.Port),
-		"--http-host=0.0.0.0",
+		fmt.Sprintf("--http-port=%d", ports.HTTP),
+		"--http-host=" + ports.BindAddress,
 		fmt.Sprintf("--db-path=%s", filepath.Join(constants.InstallStarknetDir, "juno", "database")),
 		fmt.Sprintf("--eth-node=%s", c.config.EthNode),
 		fmt.Sprintf("--ws=%t", c.isValidatorNode),
-		"--ws-port=6061",
-		"--ws-host=0.0.0.0",
+		fmt.Sprintf("--ws-port=%d", ports.WS),
+		"--ws-host=" + ports.BindAddress,
 	}
 
 	// Add network configuration
@@ -72,4 +77,3 @@ func (c *JunoClient) buildJunoArgs() []string {
 
 	return args
 }
-
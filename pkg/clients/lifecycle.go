@@ -0,0 +1,138 @@
+package clients
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// defaultStopTimeouts is how long each client gets to shut down after SIGTERM
+// before it is killed. Execution clients may need minutes to flush their database.
+var defaultStopTimeouts = map[types.ClientType]time.Duration{
+	types.ClientGeth:           5 * time.Minute,
+	types.ClientReth:           5 * time.Minute,
+	types.ClientLighthouse:     2 * time.Minute,
+	types.ClientPrysm:          2 * time.Minute,
+	types.ClientJuno:           2 * time.Minute,
+	types.ClientStarkValidator: 30 * time.Second,
+}
+
+// StopTimeout returns the configured stop timeout of a client, falling back to its default
+func StopTimeout(cfg types.StarkNodeKitConfig, client types.ClientType) time.Duration {
+	var configured time.Duration
+	switch client {
+	case cfg.ExecutionCientSettings.Name:
+		configured = cfg.ExecutionCientSettings.StopTimeout
+	case cfg.ConsensusCientSettings.Name:
+		configured = cfg.ConsensusCientSettings.StopTimeout
+	case types.ClientJuno:
+		configured = cfg.JunoConfig.StopTimeout
+	case types.ClientStarkValidator:
+		configured = cfg.ValidatorConfig.StopTimeout
+	}
+	return stopTimeoutOrDefault(configured, client)
+}
+
+// NewClient builds a single configured client. It fails if the client is not
+// the one selected in the config.
+func NewClient(cfg types.StarkNodeKitConfig, client types.ClientType) (types.IClient, error) {
+	switch client {
+	case types.ClientGeth, types.ClientReth:
+		if cfg.ExecutionCientSettings.Name != client {
+			return nil, fmt.Errorf("configured execution client is %s, not %s", cfg.ExecutionCientSettings.Name, client)
+		}
+		return NewExecutionClient(cfg.ExecutionCientSettings, cfg.Network)
+	case types.ClientLighthouse, types.ClientPrysm:
+		if cfg.ConsensusCientSettings.Name != client {
+			return nil, fmt.Errorf("configured consensus client is %s, not %s", cfg.ConsensusCientSettings.Name, client)
+		}
+		return NewConsensusClient(cfg.ConsensusCientSettings, cfg.Network, EngineURL(cfg))
+	case types.ClientJuno:
+		return NewJunoClient(cfg.JunoConfig, cfg.Network, cfg.IsValidatorNode)
+	case types.ClientStarkValidator:
+		if !cfg.IsValidatorNode {
+			return nil, fmt.Errorf("this is not a validator node")
+		}
+		return NewValidatorClient(cfg.ValidatorConfig, cfg.JunoConfig)
+	default:
+		return nil, fmt.Errorf("unknown client: %s", client)
+	}
+}
+
+// StartClient starts a configured client in the background and returns its PID.
+// Its pre_start hook runs first and aborts the start if it fails.
+func StartClient(cfg types.StarkNodeKitConfig, client types.ClientType) (int, error) {
+	if info := process.GetProcessInfo(string(client)); info != nil {
+		return info.PID, fmt.Errorf("%s is already running (PID %d)", client, info.PID)
+	}
+	c, err := NewClient(cfg, client)
+	if err != nil {
+		return 0, err
+	}
+	if err := RunHook(cfg, client, HookPreStart, 0); err != nil {
+		return 0, err
+	}
+	if err := c.Start(); err != nil {
+		return 0, fmt.Errorf("error starting %s: %w", client, err)
+	}
+	record, err := process.LoadRecord(string(client))
+	if err != nil || record == nil {
+		return 0, fmt.Errorf("%s was started but is not registered", client)
+	}
+	if err := RunHook(cfg, client, HookPostStart, record.PID); err != nil {
+		log.Print(utils.Yellow(err.Error()))
+	}
+	return record.PID, nil
+}
+
+// StopClient gracefully stops a running client using its configured stop timeout.
+// It returns nil if the client was not running. Its pre_stop hook runs first
+// and aborts the stop if it fails.
+func StopClient(cfg types.StarkNodeKitConfig, client types.ClientType) (*types.StopResult, error) {
+	info := process.GetProcessInfo(string(client))
+	if info == nil {
+		return nil, nil
+	}
+	if err := RunHook(cfg, client, HookPreStop, info.PID); err != nil {
+		return nil, err
+	}
+	result, err := process.StopClient(info.PID, StopTimeout(cfg, client))
+	if err != nil {
+		return &result, fmt.Errorf("failed to stop %s (PID %d): %w", client, info.PID, err)
+	}
+	// The files are removed after startup, unless starknode-kit died meanwhile
+	if c, err := NewClient(cfg, client); err == nil {
+		process.RemoveClientFiles(c.Spec().Files)
+	}
+	if err := RunHook(cfg, client, HookPostStop, info.PID); err != nil {
+		log.Print(utils.Yellow(err.Error()))
+	}
+	return &result, nil
+}
+
+// RestartClient stops a configured client if it is running, waits for it to
+// exit and starts it again with the current config. It returns the new PID.
+func RestartClient(cfg types.StarkNodeKitConfig, client types.ClientType) (int, error) {
+	// Build the client first so a bad config doesn't leave it stopped
+	if _, err := NewClient(cfg, client); err != nil {
+		return 0, err
+	}
+	if _, err := StopClient(cfg, client); err != nil {
+		return 0, err
+	}
+	return StartClient(cfg, client)
+}
+
+func stopTimeoutOrDefault(configured time.Duration, client types.ClientType) time.Duration {
+	if configured > 0 {
+		return configured
+	}
+	if timeout, ok := defaultStopTimeouts[client]; ok {
+		return timeout
+	}
+	return 30 * time.Second
+}
@@ -0,0 +1,22 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestRestartClientNotConfigured(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	cfg := types.StarkNodeKitConfig{
+		ExecutionCientSettings: types.ClientConfig{Name: types.ClientGeth},
+	}
+
+	if _, err := RestartClient(cfg, types.ClientReth); err == nil {
+		t.Error("expected restarting a client that is not configured to fail")
+	}
+	if _, err := RestartClient(cfg, types.ClientStarkValidator); err == nil {
+		t.Error("expected restarting the validator on a non-validator node to fail")
+	}
+}
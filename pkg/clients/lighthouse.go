@@ -2,20 +2,28 @@ package clients
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 )
 
 // Configuration options for prysm
 type lightHouseConfig struct {
 	port                []int // [quic/tcp, udp]
 	consensusCheckpoint string
+	ports               types.PortsConfig
+	engineURL           string // engine API of the execution client
 	network             string
+	stopTimeout         time.Duration
+	logs                types.LogConfig
+	extraArgs           []string
+	env                 []string
+	resources           types.ResourcesConfig
 }
 
 func (_ lightHouseConfig) getCommand() string {
@@ -35,7 +43,7 @@ func (c *lightHouseConfig) buildArgs() []string {
 		fmt.Sprintf("--port=%d", c.port[0]),
 		fmt.Sprintf("--quic-port=%d", c.port[1]),
 		"--execution-endpoint",
-		"http://localhost:8551",
+		c.engineURL,
 		"--checkpoint-sync-url",
 		c.consensusCheckpoint,
 		"--checkpoint-sync-url-timeout",
@@ -45,10 +53,14 @@ func (c *lightHouseConfig) buildArgs() []string {
 		constants.JWTPath,
 		"--metrics",
 		"--metrics-address",
-		"127.0.0.1",
+		c.ports.MetricsAddress,
 		"--metrics-port",
-		"5054",
+		strconv.Itoa(c.ports.Metrics),
 		"--http",
+		"--http-address",
+		c.ports.BindAddress,
+		"--http-port",
+		strconv.Itoa(c.ports.HTTP),
 		"--disable-upnp", // There is currently a bug in the p2p-lib that causes panics with this enabled
 	}
 
@@ -60,17 +72,20 @@ func (c *lightHouseConfig) buildArgs() []string {
 	return args
 }
 
-func (c *lightHouseConfig) Start() error {
-	args := c.buildArgs()
-	command := c.getCommand()
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	logFilePath := filepath.Join(constants.InstallClientsDir, "lighthouse", "logs", fmt.Sprintf("lighthouse_%s.log", timestamp))
-
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
+// Spec returns the resolved command used to launch the client
+func (c *lightHouseConfig) Spec() types.ClientSpec {
+	return types.ClientSpec{
+		Name:        string(types.ClientLighthouse),
+		Command:     c.getCommand(),
+		Args:        mergeArgs(c.buildArgs(), c.extraArgs),
+		Env:         c.env,
+		LogDir:      filepath.Join(constants.InstallClientsDir, "lighthouse", "logs"),
+		Logs:        c.logs,
+		StopTimeout: c.stopTimeout,
+		Resources:   c.resources,
 	}
-
-	return process.StartClient("lighthouse", command, logFile, args...)
 }
 
+func (c *lightHouseConfig) Start() error {
+	return process.StartClient(c.Spec())
+}
@@ -0,0 +1,106 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// livenessPollInterval is how often a freshly started client is checked
+const livenessPollInterval = 500 * time.Millisecond
+
+// StartupError is returned when a client dies or never answers right after
+// being started. LogFile points at its output for diagnosis.
+type StartupError struct {
+	Client  types.ClientType
+	LogFile string
+	Err     error
+}
+
+func (e *StartupError) Error() string {
+	return fmt.Sprintf("%s failed to start: %v", e.Client, e.Err)
+}
+
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+// HealthProbes returns the probes that show a client is up and serving
+// requests. Unlike ReadinessProbes they don't wait for the client to sync.
+func HealthProbes(cfg types.StarkNodeKitConfig, client types.ClientType) []ReadinessProbe {
+	ports := cfg.Ports(client)
+	switch client {
+	case types.ClientGeth, types.ClientReth:
+		rpcURL := ports.HTTPURL()
+		return []ReadinessProbe{{
+			Description: "HTTP RPC on " + rpcURL,
+			Check:       func(ctx context.Context) error { return rpcProbe(ctx, rpcURL, "net_version", nil) },
+		}}
+	case types.ClientLighthouse, types.ClientPrysm:
+		return []ReadinessProbe{{
+			Description: "beacon node API on " + ports.HTTPURL(),
+			Check:       beaconHealthProbe(ports.HTTPURL() + "/eth/v1/node/health"),
+		}}
+	case types.ClientJuno:
+		rpcURL := ports.HTTPURL()
+		return []ReadinessProbe{{
+			Description: "Juno HTTP RPC on " + rpcURL,
+			Check:       func(ctx context.Context) error { return rpcProbe(ctx, rpcURL, "juno_version", nil) },
+		}}
+	default:
+		return nil
+	}
+}
+
+// VerifyStarted watches a client that was just started. It fails with a
+// *StartupError if the process exits within the grace period or its health
+// probes don't pass within healthTimeout of the call.
+func VerifyStarted(ctx context.Context, cfg types.StarkNodeKitConfig, client types.ClientType, grace, healthTimeout time.Duration) error {
+	record, err := process.LoadRecord(string(client))
+	if err != nil || record == nil {
+		return &StartupError{Client: client, Err: fmt.Errorf("no process record found")}
+	}
+	fail := func(err error) error {
+		return &StartupError{Client: client, LogFile: record.LogFile, Err: err}
+	}
+
+	start := time.Now()
+	probes := HealthProbes(cfg, client)
+	passed := 0
+	var probeErr error
+	for {
+		if !process.IsRecordRunning(*record) {
+			uptime := time.Since(start).Round(100 * time.Millisecond)
+			if code, ok := process.ReapExitCode(record.PID); ok {
+				return fail(fmt.Errorf("exited with code %d after %s", code, uptime))
+			}
+			return fail(fmt.Errorf("exited after %s", uptime))
+		}
+
+		if passed < len(probes) {
+			checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			probeErr = probes[passed].Check(checkCtx)
+			cancel()
+			if probeErr == nil {
+				passed++
+			}
+		}
+
+		elapsed := time.Since(start)
+		if passed == len(probes) && elapsed >= grace {
+			return nil
+		}
+		if passed < len(probes) && elapsed >= max(grace, healthTimeout) {
+			return fail(fmt.Errorf("still running but %s did not respond within %s (last error: %v)", probes[passed].Description, healthTimeout, probeErr))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(livenessPollInterval):
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func launchTestClient(t *testing.T, client types.ClientType, script string) *process.LaunchedClient {
+	t.Helper()
+	launched, err := process.LaunchClient(types.ClientSpec{
+		Name:    string(client),
+		Command: "/bin/sh",
+		Args:    []string{"-c", script},
+		LogDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to launch test client: %v", err)
+	}
+	t.Cleanup(func() { process.StopClient(launched.Cmd.Process.Pid, time.Second) })
+	return launched
+}
+
+func TestVerifyStartedReportsCrash(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	launched := launchTestClient(t, types.ClientStarkValidator, "echo bad flag; exit 3")
+
+	err := VerifyStarted(context.Background(), types.StarkNodeKitConfig{}, types.ClientStarkValidator, 5*time.Second, time.Minute)
+	var startupErr *StartupError
+	if !errors.As(err, &startupErr) || !strings.Contains(err.Error(), "exited with code 3") {
+		t.Fatalf("expected the crash and its exit code to be reported, got %v", err)
+	}
+	if startupErr.LogFile != launched.LogFile {
+		t.Errorf("expected log file %s, got %s", launched.LogFile, startupErr.LogFile)
+	}
+}
+
+func TestVerifyStartedSurvivesGracePeriod(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	launchTestClient(t, types.ClientStarkValidator, "exec sleep 30")
+
+	if err := VerifyStarted(context.Background(), types.StarkNodeKitConfig{}, types.ClientStarkValidator, 300*time.Millisecond, time.Minute); err != nil {
+		t.Fatalf("expected a running client to pass, got %v", err)
+	}
+}
+
+func TestVerifyStartedHealthTimeout(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	// Reserve a port nothing listens on
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	cfg := types.StarkNodeKitConfig{ExecutionCientSettings: types.ClientConfig{
+		Name:   types.ClientGeth,
+		Listen: types.PortsConfig{HTTP: port, BindAddress: "127.0.0.1"},
+	}}
+	launchTestClient(t, types.ClientGeth, "exec sleep 30")
+
+	err = VerifyStarted(context.Background(), cfg, types.ClientGeth, 200*time.Millisecond, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "did not respond within 1s") {
+		t.Fatalf("expected the missing RPC endpoint to be reported, got %v", err)
+	}
+}
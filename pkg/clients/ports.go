@@ -0,0 +1,122 @@
+package clients
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// Listener is a socket a client opens when it starts
+type Listener struct {
+	Client   types.ClientType
+	Name     string
+	Address  string
+	Port     int
+	Protocol string // tcp or udp
+	RPC      bool   // an API that should not be reachable from the internet
+}
+
+func (l Listener) String() string {
+	return fmt.Sprintf("%s %s (%s/%s)", l.Client, l.Name, net.JoinHostPort(l.Address, fmt.Sprint(l.Port)), l.Protocol)
+}
+
+// Listeners returns the sockets a configured client opens, derived from the
+// same settings used to build its command line
+func Listeners(cfg types.StarkNodeKitConfig, client types.ClientType) []Listener {
+	ports := cfg.Ports(client)
+	var listeners []Listener
+	add := func(name, address string, port int, protocols ...string) {
+		if port == 0 {
+			return
+		}
+		for _, protocol := range protocols {
+			listeners = append(listeners, Listener{Client: client, Name: name, Address: address, Port: port, Protocol: protocol})
+		}
+	}
+	addRPC := func(name, address string, port int) {
+		if port != 0 {
+			listeners = append(listeners, Listener{Client: client, Name: name, Address: address, Port: port, Protocol: "tcp", RPC: true})
+		}
+	}
+
+	switch client {
+	case types.ClientGeth, types.ClientReth:
+		if p2p := cfg.ExecutionCientSettings.Port; len(p2p) > 0 {
+			add("p2p", "0.0.0.0", p2p[0], "tcp", "udp")
+		}
+		addRPC("http rpc", ports.BindAddress, ports.HTTP)
+		addRPC("auth rpc", ports.BindAddress, ports.AuthRPC)
+		add("metrics", ports.MetricsAddress, ports.Metrics, "tcp")
+	case types.ClientLighthouse:
+		if p2p := cfg.ConsensusCientSettings.Port; len(p2p) > 1 {
+			add("p2p", "0.0.0.0", p2p[0], "tcp", "udp")
+			add("quic", "0.0.0.0", p2p[1], "udp")
+		}
+		addRPC("beacon api", ports.BindAddress, ports.HTTP)
+		add("metrics", ports.MetricsAddress, ports.Metrics, "tcp")
+	case types.ClientPrysm:
+		if p2p := cfg.ConsensusCientSettings.Port; len(p2p) > 1 {
+			add("p2p", "0.0.0.0", p2p[0], "tcp", "udp")
+			add("p2p discovery", "0.0.0.0", p2p[1], "udp")
+		}
+		addRPC("beacon api", ports.BindAddress, ports.HTTP)
+		addRPC("grpc", "127.0.0.1", ports.GRPC)
+		add("metrics", ports.MetricsAddress, ports.Metrics, "tcp")
+	case types.ClientJuno:
+		addRPC("http rpc", ports.BindAddress, ports.HTTP)
+		if cfg.IsValidatorNode {
+			addRPC("websocket", ports.BindAddress, ports.WS)
+		}
+	}
+	return listeners
+}
+
+// ValidatePorts reports listeners of the configured clients that would try to
+// bind the same port, either within one client or across clients
+func ValidatePorts(cfg types.StarkNodeKitConfig) error {
+	var all []Listener
+	for _, client := range []types.ClientType{
+		cfg.ExecutionCientSettings.Name,
+		cfg.ConsensusCientSettings.Name,
+	} {
+		if client != "" {
+			all = append(all, Listeners(cfg, client)...)
+		}
+	}
+	if cfg.JunoConfig.EthNode != "" {
+		all = append(all, Listeners(cfg, types.ClientJuno)...)
+	}
+
+	var conflicts []string
+	for i, a := range all {
+		for _, b := range all[i+1:] {
+			if a.Port == b.Port && a.Protocol == b.Protocol && addressesOverlap(a.Address, b.Address) {
+				conflicts = append(conflicts, fmt.Sprintf("%s conflicts with %s", a, b))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("port conflicts in config: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// addressesOverlap reports whether two bind addresses would compete for the
+// same port. An unspecified address binds every interface.
+func addressesOverlap(a, b string) bool {
+	if isUnspecified(a) || isUnspecified(b) {
+		return true
+	}
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA != nil && ipB != nil {
+		return ipA.Equal(ipB)
+	}
+	return a == b
+}
+
+func isUnspecified(address string) bool {
+	ip := net.ParseIP(address)
+	return address == "" || (ip != nil && ip.IsUnspecified())
+}
@@ -0,0 +1,145 @@
+package clients
+
+import (
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func portsConfig() types.StarkNodeKitConfig {
+	return types.StarkNodeKitConfig{
+		ExecutionCientSettings: types.ClientConfig{Name: types.ClientGeth, Port: []int{30303}},
+		ConsensusCientSettings: types.ClientConfig{Name: types.ClientLighthouse, Port: []int{9000, 9001}},
+		JunoConfig:             types.JunoConfig{EthNode: "ws://localhost:8546"},
+	}
+}
+
+func TestValidatePorts(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*types.StarkNodeKitConfig)
+		conflict string
+	}{
+		{"defaults", func(*types.StarkNodeKitConfig) {}, ""},
+		{
+			"juno http on the execution rpc port",
+			func(cfg *types.StarkNodeKitConfig) { cfg.JunoConfig.Port = 8545 },
+			"geth http rpc (0.0.0.0:8545/tcp) conflicts with juno http rpc (0.0.0.0:8545/tcp)",
+		},
+		{
+			"beacon api on the consensus p2p port",
+			func(cfg *types.StarkNodeKitConfig) { cfg.ConsensusCientSettings.Listen.HTTP = 9000 },
+			"lighthouse p2p (0.0.0.0:9000/tcp) conflicts with lighthouse beacon api (127.0.0.1:9000/tcp)",
+		},
+		{
+			"same port on different addresses",
+			func(cfg *types.StarkNodeKitConfig) {
+				cfg.ExecutionCientSettings.Listen = types.PortsConfig{BindAddress: "127.0.0.1", MetricsAddress: "127.0.0.2", Metrics: 8545}
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := portsConfig()
+			tt.modify(&cfg)
+			err := ValidatePorts(cfg)
+			if tt.conflict == "" {
+				if err != nil {
+					t.Fatalf("expected no conflicts, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.conflict) {
+				t.Fatalf("expected %q, got %v", tt.conflict, err)
+			}
+		})
+	}
+}
+
+func TestLegacyConsensusPortsAreMigrated(t *testing.T) {
+	cfg := portsConfig()
+	cfg.ConsensusCientSettings.Port = []int{5052, 9000}
+	if ValidatePorts(cfg) == nil {
+		t.Fatal("expected the legacy ports to conflict with the beacon api")
+	}
+
+	cfg.MigrateLegacyPorts()
+	if !slices.Equal(cfg.ConsensusCientSettings.Port, []int{9000, 9001}) {
+		t.Errorf("expected the default ports, got %v", cfg.ConsensusCientSettings.Port)
+	}
+	if err := ValidatePorts(cfg); err != nil {
+		t.Errorf("expected the migrated config to be valid, got %v", err)
+	}
+
+	// A beacon api moved off 5052 makes the old ports valid, keep them
+	cfg.ConsensusCientSettings.Port = []int{5052, 9000}
+	cfg.ConsensusCientSettings.Listen.HTTP = 15052
+	cfg.MigrateLegacyPorts()
+	if !slices.Equal(cfg.ConsensusCientSettings.Port, []int{5052, 9000}) {
+		t.Errorf("expected configured ports to be kept, got %v", cfg.ConsensusCientSettings.Port)
+	}
+}
+
+func TestSpecUsesConfiguredPorts(t *testing.T) {
+	cfg := portsConfig()
+	cfg.ExecutionCientSettings.Listen = types.PortsConfig{HTTP: 18545, AuthRPC: 18551, BindAddress: "127.0.0.1"}
+	cfg.ConsensusCientSettings.Listen = types.PortsConfig{HTTP: 15052}
+
+	el, err := NewExecutionClient(cfg.ExecutionCientSettings, "mainnet")
+	if err != nil {
+		t.Fatalf("NewExecutionClient failed: %v", err)
+	}
+	for _, arg := range []string{"--http.port=18545", "--authrpc.port=18551", "--http.addr=127.0.0.1", "--metrics.port=7878"} {
+		if !slices.Contains(el.Spec().Args, arg) {
+			t.Errorf("expected %s in %v", arg, el.Spec().Args)
+		}
+	}
+
+	if url := EngineURL(cfg); url != "http://127.0.0.1:18551" {
+		t.Errorf("expected the engine URL to follow the auth rpc port, got %s", url)
+	}
+	cl, err := NewConsensusClient(cfg.ConsensusCientSettings, "mainnet", EngineURL(cfg))
+	if err != nil {
+		t.Fatalf("NewConsensusClient failed: %v", err)
+	}
+	for _, arg := range []string{"http://127.0.0.1:18551", "15052"} {
+		if !slices.Contains(cl.Spec().Args, arg) {
+			t.Errorf("expected %s in %v", arg, cl.Spec().Args)
+		}
+	}
+	if url := cfg.Ports(types.ClientLighthouse).HTTPURL(); url != "http://127.0.0.1:15052" {
+		t.Errorf("expected the beacon API URL to follow the config, got %s", url)
+	}
+}
+
+func TestCheckPortsFindsConflict(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	cfg := portsConfig()
+	cfg.ExecutionCientSettings.Listen.AuthRPC = listener.Addr().(*net.TCPAddr).Port
+	conflicts, err := CheckPorts(cfg, types.ClientGeth)
+	if err != nil {
+		t.Fatalf("CheckPorts failed: %v", err)
+	}
+	// Other listeners may clash with whatever else runs on the host
+	found := slices.ContainsFunc(conflicts, func(c PortConflict) bool {
+		return c.Listener.Name == "auth rpc" && c.Socket.PID == os.Getpid()
+	})
+	if !found {
+		t.Fatalf("expected the auth rpc port to be reported as held by this process, got %+v", conflicts)
+	}
+
+	exposed := ExposedListeners(cfg, types.ClientGeth)
+	if len(exposed) != 2 || exposed[0].Name != "http rpc" || exposed[1].Name != "auth rpc" {
+		t.Errorf("expected the default rpc listeners to be reported as exposed, got %+v", exposed)
+	}
+}
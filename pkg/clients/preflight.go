@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"fmt"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// PortConflict is a listener of a client whose port is already held by
+// another process
+type PortConflict struct {
+	Listener Listener
+	Socket   process.Socket
+}
+
+func (c PortConflict) String() string {
+	return fmt.Sprintf("%s port %d/%s is already in use by %s", c.Listener.Client, c.Listener.Port, c.Listener.Protocol, c.Socket.Owner())
+}
+
+// CheckPorts reports the listeners of a client that cannot be bound because
+// another process already holds the port
+func CheckPorts(cfg types.StarkNodeKitConfig, client types.ClientType) ([]PortConflict, error) {
+	listeners := Listeners(cfg, client)
+	if len(listeners) == 0 {
+		return nil, nil
+	}
+	sockets, err := process.ListeningSockets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open ports: %w", err)
+	}
+
+	var conflicts []PortConflict
+	for _, l := range listeners {
+		for _, s := range sockets {
+			if l.Port == s.Port && l.Protocol == s.Protocol && addressesOverlap(l.Address, s.Address) {
+				conflicts = append(conflicts, PortConflict{Listener: l, Socket: s})
+				break
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// ExposedListeners returns the RPC listeners of a client that bind every
+// interface and are reachable from other hosts unless a firewall blocks them
+func ExposedListeners(cfg types.StarkNodeKitConfig, client types.ClientType) []Listener {
+	var exposed []Listener
+	for _, l := range Listeners(cfg, client) {
+		if l.RPC && isUnspecified(l.Address) {
+			exposed = append(exposed, l)
+		}
+	}
+	return exposed
+}
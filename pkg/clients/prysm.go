@@ -2,20 +2,28 @@ package clients
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 )
 
 // Configuration options for prysm
 type prysmConfig struct {
 	port                []int // [quic/tcp, udp]
 	consensusCheckpoint string
+	ports               types.PortsConfig
+	engineURL           string // engine API of the execution client
 	network             string
+	stopTimeout         time.Duration
+	logs                types.LogConfig
+	extraArgs           []string
+	env                 []string
+	resources           types.ResourcesConfig
 }
 
 func (_ prysmConfig) getCommand() string {
@@ -35,18 +43,19 @@ func (c *prysmConfig) buildArgs() []string {
 		fmt.Sprintf("--p2p-quic-port=%d", c.port[0]),
 		fmt.Sprintf("--p2p-tcp-port=%d", c.port[0]),
 		"--execution-endpoint",
-		"http://localhost:8551",
-		"--grpc-gateway-host=0.0.0.0",
-		"--grpc-gateway-port=5052",
+		c.engineURL,
+		"--grpc-gateway-host=" + c.ports.BindAddress,
+		fmt.Sprintf("--grpc-gateway-port=%d", c.ports.HTTP),
+		fmt.Sprintf("--rpc-port=%d", c.ports.GRPC),
 		fmt.Sprintf("--checkpoint-sync-url=%s", c.consensusCheckpoint),
 		fmt.Sprintf("--genesis-beacon-api-url=%s", c.consensusCheckpoint),
 		"--accept-terms-of-use=true",
 		"--jwt-secret",
 		constants.JWTPath,
 		"--monitoring-host",
-		"127.0.0.1",
+		c.ports.MetricsAddress,
 		"--monitoring-port",
-		"5054",
+		strconv.Itoa(c.ports.Metrics),
 	}
 
 	// TODO still too large
@@ -57,18 +66,20 @@ func (c *prysmConfig) buildArgs() []string {
 	return args
 }
 
-// prysm.go
-func (c *prysmConfig) Start() error {
-	args := c.buildArgs()
-	command := c.getCommand()
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	logFilePath := filepath.Join(constants.InstallClientsDir, "prysm", "logs", fmt.Sprintf("prysm_%s.log", timestamp))
-
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
+// Spec returns the resolved command used to launch the client
+func (c *prysmConfig) Spec() types.ClientSpec {
+	return types.ClientSpec{
+		Name:        string(types.ClientPrysm),
+		Command:     c.getCommand(),
+		Args:        mergeArgs(c.buildArgs(), c.extraArgs),
+		Env:         c.env,
+		LogDir:      filepath.Join(constants.InstallClientsDir, "prysm", "logs"),
+		Logs:        c.logs,
+		StopTimeout: c.stopTimeout,
+		Resources:   c.resources,
 	}
-
-	return process.StartClient("prysm", command, logFile, args...)
 }
 
+func (c *prysmConfig) Start() error {
+	return process.StartClient(c.Spec())
+}
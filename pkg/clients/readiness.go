@@ -0,0 +1,171 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// readinessPollInterval is how often a failing readiness probe is retried
+const readinessPollInterval = 2 * time.Second
+
+// ReadinessProbe checks one condition dependents of a client wait for
+type ReadinessProbe struct {
+	Description string
+	Check       func(ctx context.Context) error
+}
+
+// ReadinessProbes returns the probes that must pass before a client's dependents are started
+func ReadinessProbes(cfg types.StarkNodeKitConfig, client types.ClientType) []ReadinessProbe {
+	ports := cfg.Ports(client)
+	switch client {
+	case types.ClientGeth, types.ClientReth:
+		return []ReadinessProbe{{
+			Description: "engine API on " + ports.AuthRPCAddress(),
+			Check:       func(ctx context.Context) error { return dialProbe(ctx, ports.AuthRPCAddress()) },
+		}}
+	case types.ClientLighthouse, types.ClientPrysm:
+		return []ReadinessProbe{{
+			Description: "beacon node API on " + ports.HTTPURL(),
+			Check:       beaconHealthProbe(ports.HTTPURL() + "/eth/v1/node/health"),
+		}}
+	case types.ClientJuno:
+		rpcURL := ports.HTTPURL()
+		return []ReadinessProbe{
+			{
+				Description: "Juno HTTP RPC on " + rpcURL,
+				Check:       func(ctx context.Context) error { return rpcProbe(ctx, rpcURL, "juno_version", nil) },
+			},
+			{
+				Description: "Juno to finish syncing",
+				Check:       junoSyncedProbe(rpcURL),
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// WaitReady blocks until every readiness probe of a client passes or ctx is done.
+// onWait is called before each probe so callers can report progress.
+func WaitReady(ctx context.Context, cfg types.StarkNodeKitConfig, client types.ClientType, onWait func(ReadinessProbe)) error {
+	for _, probe := range ReadinessProbes(cfg, client) {
+		if onWait != nil {
+			onWait(probe)
+		}
+		if err := waitForProbe(ctx, probe); err != nil {
+			return fmt.Errorf("%s is not ready (%s): %w", client, probe.Description, err)
+		}
+	}
+	return nil
+}
+
+func waitForProbe(ctx context.Context, probe ReadinessProbe) error {
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := probe.Check(checkCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-time.After(readinessPollInterval):
+		}
+	}
+}
+
+func dialProbe(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// beaconHealthProbe passes once the beacon node answers its health endpoint.
+// 206 means the node is up but still syncing, which is enough for dependents.
+func beaconHealthProbe(url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+			return fmt.Errorf("health endpoint returned %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+func junoSyncedProbe(rpcURL string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var result json.RawMessage
+		if err := rpcProbe(ctx, rpcURL, "starknet_syncing", &result); err != nil {
+			return err
+		}
+		// starknet_syncing returns false once the node is synced, a status object otherwise
+		var syncing bool
+		if err := json.Unmarshal(result, &syncing); err == nil && !syncing {
+			return nil
+		}
+		var status struct {
+			Current uint64 `json:"current_block_num"`
+			Highest uint64 `json:"highest_block_num"`
+		}
+		if err := json.Unmarshal(result, &status); err == nil {
+			return fmt.Errorf("syncing, block %d of %d", status.Current, status.Highest)
+		}
+		return fmt.Errorf("syncing")
+	}
+}
+
+// rpcProbe calls a JSON-RPC method and decodes its result into out when it is not nil
+func rpcProbe(ctx context.Context, rpcURL, method string, out *json.RawMessage) error {
+	body, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  []any{},
+		"id":      1,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s failed: %s", method, rpcResp.Error.Message)
+	}
+	if out != nil {
+		*out = rpcResp.Result
+	}
+	return nil
+}
@@ -2,20 +2,28 @@ package clients
 
 import (
 	"fmt"
-	"os"
+	"net"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 )
 
 // Configuration options for Reth
 type rethConfig struct {
 	port          int
+	ports         types.PortsConfig
 	executionType string
 	network       string
+	stopTimeout   time.Duration
+	logs          types.LogConfig
+	extraArgs     []string
+	env           []string
+	resources     types.ResourcesConfig
 }
 
 // GetRethCommand returns the reth command path based on platform
@@ -34,15 +42,15 @@ func (config *rethConfig) buildArgs() []string {
 		"node",
 		"--chain", config.network,
 		"--http",
-		"--http.addr", "0.0.0.0",
-		"--http.port", "8545",
+		"--http.addr", config.ports.BindAddress,
+		"--http.port", strconv.Itoa(config.ports.HTTP),
 		"--http.api", "eth,net,admin",
 		"--http.corsdomain", "*",
-		"--authrpc.addr", "0.0.0.0",
-		"--authrpc.port", "8551",
+		"--authrpc.addr", config.ports.BindAddress,
+		"--authrpc.port", strconv.Itoa(config.ports.AuthRPC),
 		"--authrpc.jwtsecret", constants.JWTPath,
 		"--port", fmt.Sprintf("%d", config.port),
-		"--metrics", "0.0.0.0:7878",
+		"--metrics", net.JoinHostPort(config.ports.MetricsAddress, strconv.Itoa(config.ports.Metrics)),
 	}
 
 	// Add execution type specific arguments
@@ -57,20 +65,20 @@ func (config *rethConfig) buildArgs() []string {
 	return args
 }
 
-func (c *rethConfig) Start() error {
-	args := c.buildArgs()
-	command := c.getCommand()
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	logFilePath := filepath.Join(
-		constants.InstallClientsDir,
-		"reth",
-		"logs",
-		fmt.Sprintf("reth_%s.log", timestamp))
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
+// Spec returns the resolved command used to launch the client
+func (c *rethConfig) Spec() types.ClientSpec {
+	return types.ClientSpec{
+		Name:        string(types.ClientReth),
+		Command:     c.getCommand(),
+		Args:        mergeArgs(c.buildArgs(), c.extraArgs),
+		Env:         c.env,
+		LogDir:      filepath.Join(constants.InstallClientsDir, "reth", "logs"),
+		Logs:        c.logs,
+		StopTimeout: c.stopTimeout,
+		Resources:   c.resources,
 	}
-
-	return process.StartClient("reth", command, logFile, args...)
 }
 
+func (c *rethConfig) Start() error {
+	return process.StartClient(c.Spec())
+}
@@ -0,0 +1,156 @@
+package clients
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// StackEntry is one configured client and the clients it needs to be ready
+// before it can start
+type StackEntry struct {
+	Type      types.ClientType
+	DependsOn []types.ClientType
+}
+
+// StackClient is a StackEntry together with the client used to launch it
+type StackClient struct {
+	StackEntry
+	Client types.IClient
+}
+
+// StackOrder returns the configured clients in dependency order. The consensus
+// client depends on the execution client, Juno depends on it when its eth_node
+// is local, and the staking validator depends on a local Juno.
+func StackOrder(cfg types.StarkNodeKitConfig) ([]StackEntry, error) {
+	var entries []StackEntry
+	el := cfg.ExecutionCientSettings.Name
+	junoConfigured := cfg.JunoConfig.EthNode != ""
+
+	if el != "" {
+		entries = append(entries, StackEntry{Type: el})
+	}
+	if cl := cfg.ConsensusCientSettings.Name; cl != "" {
+		entry := StackEntry{Type: cl}
+		if el != "" {
+			entry.DependsOn = append(entry.DependsOn, el)
+		}
+		entries = append(entries, entry)
+	}
+	if junoConfigured {
+		entry := StackEntry{Type: types.ClientJuno}
+		if el != "" && isLocalURL(cfg.JunoConfig.EthNode) {
+			entry.DependsOn = append(entry.DependsOn, el)
+		}
+		entries = append(entries, entry)
+	}
+	if cfg.IsValidatorNode {
+		entry := StackEntry{Type: types.ClientStarkValidator}
+		if junoConfigured {
+			entry.DependsOn = append(entry.DependsOn, types.ClientJuno)
+		}
+		entries = append(entries, entry)
+	}
+
+	return sortByDependencies(entries)
+}
+
+// StopOrder sorts client names so dependents are stopped before the clients they
+// depend on. Names that are not part of the configured stack keep their relative
+// order and go last.
+func StopOrder(cfg types.StarkNodeKitConfig, names []string) []string {
+	var order []string
+	if stack, err := StackOrder(cfg); err == nil {
+		for _, entry := range slices.Backward(stack) {
+			order = append(order, string(entry.Type))
+		}
+	}
+	rank := func(name string) int {
+		if i := slices.Index(order, name); i >= 0 {
+			return i
+		}
+		return len(order)
+	}
+
+	sorted := slices.Clone(names)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		return rank(a) - rank(b)
+	})
+	return sorted
+}
+
+// NewStack builds every configured client in dependency order
+func NewStack(cfg types.StarkNodeKitConfig) ([]StackClient, error) {
+	order, err := StackOrder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	configured, err := NewConfiguredClients(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]types.IClient, len(configured))
+	for _, client := range configured {
+		byName[client.Spec().Name] = client
+	}
+
+	stack := make([]StackClient, 0, len(order))
+	for _, entry := range order {
+		client, ok := byName[string(entry.Type)]
+		if !ok {
+			return nil, fmt.Errorf("no client configured for %s", entry.Type)
+		}
+		stack = append(stack, StackClient{StackEntry: entry, Client: client})
+	}
+	return stack, nil
+}
+
+// sortByDependencies orders entries so every client comes after the clients it
+// depends on, keeping the original order between independent clients
+func sortByDependencies(entries []StackEntry) ([]StackEntry, error) {
+	var sorted []StackEntry
+	placed := make(map[types.ClientType]bool, len(entries))
+
+	for len(sorted) < len(entries) {
+		progress := false
+		for _, entry := range entries {
+			if placed[entry.Type] {
+				continue
+			}
+			ready := true
+			for _, dep := range entry.DependsOn {
+				if !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, entry)
+				placed[entry.Type] = true
+				progress = true
+			}
+		}
+		if !progress {
+			var pending []string
+			for _, entry := range entries {
+				if !placed[entry.Type] {
+					pending = append(pending, string(entry.Type))
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle or missing dependency between clients: %v", pending)
+		}
+	}
+	return sorted, nil
+}
+
+// isLocalURL reports whether a URL points at this machine
+func isLocalURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return slices.Contains([]string{"localhost", "127.0.0.1", "::1", "0.0.0.0"}, u.Hostname())
+}
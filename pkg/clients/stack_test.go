@@ -0,0 +1,124 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestStackOrder(t *testing.T) {
+	cfg := types.StarkNodeKitConfig{
+		IsValidatorNode:        true,
+		ExecutionCientSettings: types.ClientConfig{Name: types.ClientGeth},
+		ConsensusCientSettings: types.ClientConfig{Name: types.ClientLighthouse},
+		JunoConfig:             types.JunoConfig{Port: 6060, EthNode: "ws://localhost:8546"},
+	}
+
+	order, err := StackOrder(cfg)
+	if err != nil {
+		t.Fatalf("StackOrder failed: %v", err)
+	}
+
+	expected := []struct {
+		client    types.ClientType
+		dependsOn []types.ClientType
+	}{
+		{types.ClientGeth, nil},
+		{types.ClientLighthouse, []types.ClientType{types.ClientGeth}},
+		{types.ClientJuno, []types.ClientType{types.ClientGeth}},
+		{types.ClientStarkValidator, []types.ClientType{types.ClientJuno}},
+	}
+	if len(order) != len(expected) {
+		t.Fatalf("expected %d clients, got %d: %+v", len(expected), len(order), order)
+	}
+	for i, e := range expected {
+		if order[i].Type != e.client {
+			t.Errorf("expected client %d to be %s, got %s", i, e.client, order[i].Type)
+		}
+		if len(order[i].DependsOn) != len(e.dependsOn) {
+			t.Errorf("expected %s to depend on %v, got %v", e.client, e.dependsOn, order[i].DependsOn)
+		}
+	}
+}
+
+func TestStopOrder(t *testing.T) {
+	cfg := types.StarkNodeKitConfig{
+		ExecutionCientSettings: types.ClientConfig{Name: types.ClientGeth},
+		ConsensusCientSettings: types.ClientConfig{Name: types.ClientLighthouse},
+		JunoConfig:             types.JunoConfig{Port: 6060, EthNode: "ws://localhost:8546"},
+	}
+
+	got := StopOrder(cfg, []string{"geth", "reth", "juno", "lighthouse"})
+	expected := []string{"juno", "lighthouse", "geth", "reth"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected stop order %v, got %v", expected, got)
+	}
+}
+
+func TestStackOrderRemoteEthNode(t *testing.T) {
+	cfg := types.StarkNodeKitConfig{
+		ExecutionCientSettings: types.ClientConfig{Name: types.ClientReth},
+		JunoConfig:             types.JunoConfig{Port: 6060, EthNode: "wss://eth.drpc.org"},
+	}
+
+	order, err := StackOrder(cfg)
+	if err != nil {
+		t.Fatalf("StackOrder failed: %v", err)
+	}
+	for _, entry := range order {
+		if entry.Type == types.ClientJuno && len(entry.DependsOn) != 0 {
+			t.Errorf("expected Juno with a remote eth_node to have no dependencies, got %v", entry.DependsOn)
+		}
+	}
+}
+
+func TestSortByDependencies(t *testing.T) {
+	entries := []StackEntry{
+		{Type: types.ClientStarkValidator, DependsOn: []types.ClientType{types.ClientJuno}},
+		{Type: types.ClientJuno, DependsOn: []types.ClientType{types.ClientGeth}},
+		{Type: types.ClientGeth},
+	}
+	sorted, err := sortByDependencies(entries)
+	if err != nil {
+		t.Fatalf("sortByDependencies failed: %v", err)
+	}
+	want := []types.ClientType{types.ClientGeth, types.ClientJuno, types.ClientStarkValidator}
+	for i, client := range want {
+		if sorted[i].Type != client {
+			t.Errorf("expected client %d to be %s, got %s", i, client, sorted[i].Type)
+		}
+	}
+
+	cyclic := []StackEntry{
+		{Type: types.ClientGeth, DependsOn: []types.ClientType{types.ClientLighthouse}},
+		{Type: types.ClientLighthouse, DependsOn: []types.ClientType{types.ClientGeth}},
+	}
+	if _, err := sortByDependencies(cyclic); err == nil {
+		t.Error("expected an error for a dependency cycle")
+	}
+}
+
+func TestJunoSyncedProbe(t *testing.T) {
+	synced := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if synced {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":false}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"current_block_num":10,"highest_block_num":20}}`))
+	}))
+	defer server.Close()
+
+	probe := junoSyncedProbe(server.URL)
+	if err := probe(context.Background()); err == nil {
+		t.Error("expected a syncing node to fail the probe")
+	}
+	synced = true
+	if err := probe(context.Background()); err != nil {
+		t.Errorf("expected a synced node to pass the probe, got %v", err)
+	}
+}
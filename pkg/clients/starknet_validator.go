@@ -1,56 +1,110 @@
 package clients
 
 import (
-	"fmt"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/keystore"
 	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 )
 
 type StakingValidator struct {
-	Provider stakingValidatorProviderConfig
-	Wallet   stakingValidatorWalletConfig
-}
+	Wallet stakingValidatorWalletConfig
+
+	juno types.PortsConfig // the validator talks to the local Juno node
 
-type stakingValidatorProviderConfig struct {
-	starknetHttp string
-	starkentWS   string
+	stopTimeout time.Duration
+	logs        types.LogConfig
+	extraArgs   []string
+	env         []string
+	resources   types.ResourcesConfig
 }
 
 type stakingValidatorWalletConfig struct {
 	address    string
-	privatekey string
+	privatekey string // only wallets created before keystores
+	keystore   string
 }
 
 func (_ StakingValidator) getCommand() string {
 	return filepath.Join(constants.InstallStarknetDir, "starknet-staking-v2", "validator")
 }
 
+// validatorConfigFile is the config file format of the staking validator
+type validatorConfigFile struct {
+	Provider struct {
+		HTTP string `json:"http"`
+		WS   string `json:"ws"`
+	} `json:"provider"`
+	Signer struct {
+		OperationalAddress string `json:"operationalAddress"`
+		PrivateKey         string `json:"privateKey"`
+	} `json:"signer"`
+}
+
+// configPath is where the validator's config file is written. It holds the
+// private key, which would be readable by every user in /proc/<pid>/cmdline
+// if it was passed as a flag, so it lives on the runtime tmpfs only until the
+// validator has read it.
+func (_ StakingValidator) configPath() string {
+	dir, err := keystore.RuntimeDir()
+	if err != nil {
+		// Writing the file fails with a clear error, keep the args usable meanwhile
+		dir = filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "starknode-kit")
+	}
+	return filepath.Join(dir, string(types.ClientStarkValidator)+".json")
+}
+
+// legacyConfigPath is where older versions kept the config file, on disk
+func (_ StakingValidator) legacyConfigPath() string {
+	return filepath.Join(constants.StateDir, "secrets", string(types.ClientStarkValidator)+".json")
+}
+
 func (c StakingValidator) buildArgs() []string {
 	args := []string{
-		"--provider-http", c.Provider.starknetHttp,
-		"--provider-ws", c.Provider.starkentWS,
+		"--config", c.configPath(),
+		"--provider-http", c.juno.HTTPURL(),
+		"--provider-ws", c.juno.WSURL(),
 		"--signer-op-address", c.Wallet.address,
-		"--signer-priv-key", c.Wallet.privatekey,
 	}
 	return args
 }
 
-func (c *StakingValidator) Start() error {
-	args := c.buildArgs()
-	command := c.getCommand()
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-
-	logFilePath := filepath.Join(constants.InstallStarknetDir, "starknet-staking-v2",
-		"logs",
-		fmt.Sprintf("starknet-staking-v2_%s.log", timestamp))
-	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+// buildConfigFile renders the validator's config file, decrypting the
+// operational key from its keystore
+func (c StakingValidator) buildConfigFile() ([]byte, error) {
+	os.Remove(c.legacyConfigPath())
+	privateKey, err := keystore.WalletKey(types.Wallet{PrivateKey: c.Wallet.privatekey, Keystore: c.Wallet.keystore})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	var file validatorConfigFile
+	file.Provider.HTTP = c.juno.HTTPURL()
+	file.Provider.WS = c.juno.WSURL()
+	file.Signer.OperationalAddress = c.Wallet.address
+	file.Signer.PrivateKey = privateKey
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// Spec returns the resolved command used to launch the staking validator
+func (c *StakingValidator) Spec() types.ClientSpec {
+	return types.ClientSpec{
+		Name:        string(types.ClientStarkValidator),
+		Command:     c.getCommand(),
+		Args:        mergeArgs(c.buildArgs(), c.extraArgs),
+		Env:         c.env,
+		LogDir:      filepath.Join(constants.InstallStarknetDir, "starknet-staking-v2", "logs"),
+		Logs:        c.logs,
+		StopTimeout: c.stopTimeout,
+		Resources:   c.resources,
+		Files:       []types.ClientFile{{Path: c.configPath(), Data: c.buildConfigFile}},
 	}
+}
 
-	return process.StartClient("staking-validator", command, logFile, args...)
+func (c *StakingValidator) Start() error {
+	return process.StartClient(c.Spec())
 }
@@ -30,6 +30,8 @@ var (
 	ConfigDir   = path.Join(InstallDir, "config")
 	ConfigPath  = fmt.Sprintf("%s/starknode.yaml", ConfigDir)
 	EnvFIlePath = fmt.Sprintf("%s/.starknode.env", ConfigDir)
+	StateDir    = path.Join(InstallDir, "state")
+	KeystoreDir = path.Join(InstallDir, "keystore")
 	Banner      = figure.NewColorFigure("Starknode kit", "slant", "green", true)
 
 	RPCURL = map[string]string{
@@ -53,4 +55,3 @@ func getHomeDir() string {
 	}
 	return homeDir
 }
-
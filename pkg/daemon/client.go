@@ -0,0 +1,123 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// requestTimeout bounds control requests. Stopping a client may take up to its
+// stop timeout, so it is generous.
+const requestTimeout = 15 * time.Minute
+
+// Client talks to a running daemon over its control socket
+type Client struct {
+	http *http.Client
+}
+
+// Dial connects to the daemon, returning nil if no daemon is running
+func Dial() *Client {
+	if _, err := os.Stat(SocketPath()); err != nil {
+		return nil
+	}
+	c := &Client{http: &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", SocketPath())
+			},
+		},
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.do(ctx, http.MethodGet, "/status", nil); err != nil {
+		return nil
+	}
+	return c
+}
+
+// Status returns the state of every client known to the daemon
+func (c *Client) Status() ([]ClientStatus, error) {
+	var statuses []ClientStatus
+	err := c.call(http.MethodGet, "/status", &statuses)
+	return statuses, err
+}
+
+// Start asks the daemon to start and supervise a client
+func (c *Client) Start(name string) (int, error) {
+	var resp struct {
+		PID int `json:"pid"`
+	}
+	err := c.call(http.MethodPost, "/clients/"+name+"/start", &resp)
+	return resp.PID, err
+}
+
+// Stop asks the daemon to stop a client. The result is nil if it was not running.
+func (c *Client) Stop(name string) (*types.StopResult, error) {
+	var result *types.StopResult
+	err := c.call(http.MethodPost, "/clients/"+name+"/stop", &result)
+	return result, err
+}
+
+// Restart asks the daemon to restart a client with the current config
+func (c *Client) Restart(name string) (int, error) {
+	var resp struct {
+		PID int `json:"pid"`
+	}
+	err := c.call(http.MethodPost, "/clients/"+name+"/restart", &resp)
+	return resp.PID, err
+}
+
+// Logs returns the last lines of a client's log
+func (c *Client) Logs(name string, lines int) ([]string, error) {
+	var logs []string
+	err := c.call(http.MethodGet, fmt.Sprintf("/clients/%s/logs?lines=%d", name, lines), &logs)
+	return logs, err
+}
+
+// Sync returns the sync progress of the configured clients
+func (c *Client) Sync() (SyncStatus, error) {
+	var status SyncStatus
+	err := c.call(http.MethodGet, "/sync", &status)
+	return status, err
+}
+
+func (c *Client) call(method, path string, out any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	return c.do(ctx, method, path, out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, "http://daemon"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("daemon request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return errors.New(apiErr.Error)
+		}
+		return fmt.Errorf("daemon returned %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,33 @@
+package daemon
+
+import (
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// StartClient starts a client through the daemon when one is running, so it is
+// supervised, and directly otherwise
+func StartClient(cfg types.StarkNodeKitConfig, client types.ClientType) (int, error) {
+	if c := Dial(); c != nil {
+		return c.Start(string(client))
+	}
+	return clients.StartClient(cfg, client)
+}
+
+// StopClient stops a client through the daemon when one is running, so it is
+// not restarted, and directly otherwise. It returns nil if the client was not running.
+func StopClient(cfg types.StarkNodeKitConfig, client types.ClientType) (*types.StopResult, error) {
+	if c := Dial(); c != nil {
+		return c.Stop(string(client))
+	}
+	return clients.StopClient(cfg, client)
+}
+
+// RestartClient restarts a client through the daemon when one is running and
+// directly otherwise. It returns the new PID.
+func RestartClient(cfg types.StarkNodeKitConfig, client types.ClientType) (int, error) {
+	if c := Dial(); c != nil {
+		return c.Restart(string(client))
+	}
+	return clients.RestartClient(cfg, client)
+}
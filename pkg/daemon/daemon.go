@@ -0,0 +1,277 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/supervisor"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// ClientStatus is the state of one client as reported by the daemon
+type ClientStatus struct {
+	Name    string             `json:"name"`
+	Running bool               `json:"running"`
+	Managed bool               `json:"managed"`         // started and supervised by the daemon
+	State   string             `json:"state,omitempty"` // supervisor state of managed clients
+	Process *types.ProcessInfo `json:"process,omitempty"`
+}
+
+// SyncStatus is the sync progress of the configured clients
+type SyncStatus struct {
+	Execution types.SyncInfo        `json:"execution"`
+	Consensus types.SyncInfo        `json:"consensus"`
+	Starknet  types.EthereumMetrics `json:"starknet"`
+}
+
+// Server owns the lifecycle of the clients it starts and serves the control
+// API on a Unix socket
+type Server struct {
+	opts supervisor.Options
+
+	mu      sync.Mutex
+	ctx     context.Context
+	managed map[string]*managedClient
+}
+
+type managedClient struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// SocketPath returns the path of the daemon's control socket
+func SocketPath() string {
+	return filepath.Join(constants.StateDir, "daemon.sock")
+}
+
+// NewServer creates a daemon that supervises clients with the given restart policy
+func NewServer(opts supervisor.Options) *Server {
+	return &Server{opts: opts, managed: make(map[string]*managedClient)}
+}
+
+// Serve listens on the control socket until ctx is cancelled, then stops every
+// client it manages in reverse dependency order. With startAll the whole
+// configured stack is started first.
+func (s *Server) Serve(ctx context.Context, startAll bool) error {
+	listener, err := listen()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(SocketPath())
+
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	server := &http.Server{Handler: s.routes()}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+	log.Printf(utils.Green("Daemon listening on %s"), SocketPath())
+
+	if startAll {
+		go s.startAll(ctx)
+	}
+
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(shutdownCtx)
+	s.stopAll()
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Start launches a client under the daemon's supervision and returns its PID
+func (s *Server) Start(name string) (int, error) {
+	s.mu.Lock()
+	pid, err := s.checkStartable(name)
+	s.mu.Unlock()
+	if err != nil {
+		return pid, err
+	}
+
+	cfg, err := utils.LoadConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	client, err := clients.NewClient(cfg, types.ClientType(name))
+	if err != nil {
+		return 0, err
+	}
+	// The hook may take minutes, don't hold up the other API calls meanwhile
+	if err := clients.RunHook(cfg, types.ClientType(name), clients.HookPreStart, 0); err != nil {
+		return 0, err
+	}
+
+	// Another start may have won the race while the hook ran
+	s.mu.Lock()
+	if pid, err := s.checkStartable(name); err != nil {
+		s.mu.Unlock()
+		return pid, err
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	m := &managedClient{cancel: cancel, done: make(chan struct{})}
+	s.managed[name] = m
+	s.mu.Unlock()
+
+	go func() {
+		defer close(m.done)
+		if err := supervisor.New([]types.IClient{client}, s.opts).Run(ctx); err != nil {
+			log.Printf(utils.Red("Could not supervise %s: %v"), name, err)
+		}
+	}()
+
+	pid, err = waitForRecord(name, m.done)
+	if err == nil {
+		if err := clients.RunHook(cfg, types.ClientType(name), clients.HookPostStart, pid); err != nil {
+			log.Print(utils.Yellow(err.Error()))
+		}
+	}
+	return pid, err
+}
+
+// checkStartable fails if the daemon is shutting down or the client is
+// already running, returning its PID if known. s.mu must be held.
+func (s *Server) checkStartable(name string) (int, error) {
+	if s.ctx == nil || s.ctx.Err() != nil {
+		return 0, fmt.Errorf("daemon is shutting down")
+	}
+	if m, ok := s.managed[name]; ok && !isDone(m.done) {
+		if info := process.GetProcessInfo(name); info != nil {
+			return info.PID, fmt.Errorf("%s is already running (PID %d)", name, info.PID)
+		}
+		return 0, fmt.Errorf("%s is already managed by the daemon and waiting to restart", name)
+	}
+	if info := process.GetProcessInfo(name); info != nil {
+		return info.PID, fmt.Errorf("%s is already running outside the daemon (PID %d), stop it first", name, info.PID)
+	}
+	return 0, nil
+}
+
+// Stop stops a client. Clients started outside the daemon are stopped directly.
+func (s *Server) Stop(name string) (*types.StopResult, error) {
+	cfg, _ := utils.LoadConfig()
+	s.mu.Lock()
+	m, ok := s.managed[name]
+	if !ok {
+		s.mu.Unlock()
+		return clients.StopClient(cfg, types.ClientType(name))
+	}
+
+	s.mu.Unlock()
+
+	// The hook may take minutes, don't hold up the other API calls meanwhile
+	info := process.GetProcessInfo(name)
+	if info != nil {
+		if err := clients.RunHook(cfg, types.ClientType(name), clients.HookPreStop, info.PID); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	if s.managed[name] != m {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%s was stopped or restarted while its pre-stop hook ran", name)
+	}
+	delete(s.managed, name)
+	s.mu.Unlock()
+
+	start := time.Now()
+	m.cancel()
+	<-m.done
+	if info == nil {
+		return nil, nil
+	}
+	if err := clients.RunHook(cfg, types.ClientType(name), clients.HookPostStop, info.PID); err != nil {
+		log.Print(utils.Yellow(err.Error()))
+	}
+
+	result := &types.StopResult{PID: info.PID, Method: "terminated", Duration: time.Since(start)}
+	if history, err := process.LoadHistory(name); err == nil && history != nil && len(history.Exits) > 0 {
+		if last := history.Exits[len(history.Exits)-1]; last.Stopped != "" {
+			result.Method = last.Stopped
+		}
+	}
+	return result, nil
+}
+
+// Restart stops a client if it is running and starts it again with the current config
+func (s *Server) Restart(name string) (int, error) {
+	if _, err := s.Stop(name); err != nil {
+		return 0, err
+	}
+	return s.Start(name)
+}
+
+// Status reports every configured client plus any other running client
+func (s *Server) Status() []ClientStatus {
+	cfg, _ := utils.LoadConfig()
+	var names []string
+	if order, err := clients.StackOrder(cfg); err == nil {
+		for _, entry := range order {
+			names = append(names, string(entry.Type))
+		}
+	}
+	records, _ := process.ListRecords()
+	for _, record := range records {
+		if !slices.Contains(names, record.Name) {
+			names = append(names, record.Name)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]ClientStatus, 0, len(names))
+	for _, name := range names {
+		status := ClientStatus{Name: name, Process: process.GetProcessUsage(name)}
+		status.Running = status.Process != nil
+		if m, ok := s.managed[name]; ok && !isDone(m.done) {
+			status.Managed = true
+			if history, err := process.LoadHistory(name); err == nil && history != nil {
+				status.State = history.State
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// listen binds the control socket, replacing a stale socket left by a crashed daemon
+func listen() (net.Listener, error) {
+	if err := os.MkdirAll(constants.StateDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if Dial() != nil {
+		return nil, fmt.Errorf("a daemon is already listening on %s", SocketPath())
+	}
+	os.Remove(SocketPath())
+
+	listener, err := net.Listen("unix", SocketPath())
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(SocketPath(), 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,215 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/logrotate"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// readyTimeout bounds how long startAll waits for a dependency to become ready
+const readyTimeout = 30 * time.Minute
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.Status())
+	})
+	mux.HandleFunc("GET /sync", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, syncStatus())
+	})
+	mux.HandleFunc("POST /clients/{name}/start", s.withClient(func(w http.ResponseWriter, r *http.Request, name string) {
+		pid, err := s.Start(name)
+		if err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"pid": pid})
+	}))
+	mux.HandleFunc("POST /clients/{name}/stop", s.withClient(func(w http.ResponseWriter, r *http.Request, name string) {
+		result, err := s.Stop(name)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}))
+	mux.HandleFunc("POST /clients/{name}/restart", s.withClient(func(w http.ResponseWriter, r *http.Request, name string) {
+		pid, err := s.Restart(name)
+		if err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"pid": pid})
+	}))
+	mux.HandleFunc("GET /clients/{name}/logs", s.withClient(func(w http.ResponseWriter, r *http.Request, name string) {
+		lines, err := strconv.Atoi(r.URL.Query().Get("lines"))
+		if err != nil || lines <= 0 {
+			lines = 50
+		}
+		logs, err := clientLogs(name, lines)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, logs)
+	}))
+	return mux
+}
+
+// withClient validates the {name} path value before calling the handler
+func (s *Server) withClient(handler func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := utils.ResolveClientType(r.PathValue("name"))
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		handler(w, r, string(client))
+	}
+}
+
+// startAll starts the configured stack in dependency order, waiting for each
+// dependency's readiness probes
+func (s *Server) startAll(ctx context.Context) {
+	cfg, err := utils.LoadConfig()
+	if err != nil {
+		log.Printf(utils.Red("Could not load config: %v"), err)
+		return
+	}
+	order, err := clients.StackOrder(cfg)
+	if err != nil {
+		log.Printf(utils.Red("Could not order clients: %v"), err)
+		return
+	}
+
+	ready := make(map[types.ClientType]bool)
+	for _, entry := range order {
+		for _, dep := range entry.DependsOn {
+			if ready[dep] {
+				continue
+			}
+			waitCtx, cancel := context.WithTimeout(ctx, readyTimeout)
+			err := clients.WaitReady(waitCtx, cfg, dep, func(probe clients.ReadinessProbe) {
+				log.Printf(utils.Cyan("Waiting for %s: %s"), dep, probe.Description)
+			})
+			cancel()
+			if err != nil {
+				log.Printf(utils.Red("Not starting %s: %v"), entry.Type, err)
+				return
+			}
+			ready[dep] = true
+		}
+		if _, err := s.Start(string(entry.Type)); err != nil {
+			log.Printf(utils.Yellow("Could not start %s: %v"), entry.Type, err)
+		}
+	}
+}
+
+// stopAll stops every managed client, dependents before their dependencies
+func (s *Server) stopAll() {
+	s.mu.Lock()
+	var names []string
+	for name := range s.managed {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+
+	cfg, _ := utils.LoadConfig()
+	names = clients.StopOrder(cfg, names)
+
+	for _, name := range names {
+		log.Printf(utils.Cyan("Stopping %s"), name)
+		if _, err := s.Stop(name); err != nil {
+			log.Printf(utils.Red("Could not stop %s: %v"), name, err)
+		}
+	}
+}
+
+// waitForRecord waits until a freshly supervised client shows up in the registry
+func waitForRecord(name string, done <-chan struct{}) (int, error) {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if record, err := process.LoadRecord(name); err == nil && record != nil {
+			return record.PID, nil
+		}
+		if isDone(done) {
+			return 0, fmt.Errorf("%s exited immediately, check `starknode-kit status %s`", name, name)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return 0, fmt.Errorf("%s did not start within 10s", name)
+}
+
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// clientLogs returns the last lines of the log a client is writing to
+func clientLogs(name string, lines int) ([]string, error) {
+	if record, err := process.LoadRecord(name); err == nil && record != nil && record.LogFile != "" {
+		return process.TailLog(record.LogFile, lines)
+	}
+	cfg, err := utils.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := clients.NewClient(cfg, types.ClientType(name))
+	if err != nil {
+		return nil, err
+	}
+	file, err := logrotate.LatestLogFile(client.Spec().LogDir, name)
+	if err != nil {
+		return nil, err
+	}
+	return process.TailLog(file, lines)
+}
+
+func syncStatus() SyncStatus {
+	var status SyncStatus
+	cfg, err := utils.LoadConfig()
+	if err != nil {
+		return status
+	}
+	el, cl := cfg.ExecutionCientSettings, cfg.ConsensusCientSettings
+	switch el.Name {
+	case types.ClientGeth:
+		status.Execution = utils.GetGethSyncStatus(el.Ports().HTTPURL())
+	case types.ClientReth:
+		status.Execution = utils.GetRethSyncStatus(el.Ports().HTTPURL())
+	}
+	switch cl.Name {
+	case types.ClientLighthouse:
+		status.Consensus = utils.GetLighthouseSyncStatus(cl.Ports().HTTPURL())
+	case types.ClientPrysm:
+		status.Consensus = utils.GetPrysmSyncStatus(cl.Ports().HTTPURL())
+	}
+	if cfg.JunoConfig.EthNode != "" {
+		status.Starknet = utils.GetJunoMetrics(cfg.Network, cfg.JunoConfig.Ports().HTTPURL())
+	}
+	return status
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
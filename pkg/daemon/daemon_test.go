@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/supervisor"
+)
+
+func TestServeAndDial(t *testing.T) {
+	constants.StateDir = t.TempDir()
+
+	if Dial() != nil {
+		t.Fatal("expected Dial to return nil without a daemon")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- NewServer(supervisor.DefaultOptions()).Serve(ctx, false) }()
+
+	var c *Client
+	for range 50 {
+		if c = Dial(); c != nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if c == nil {
+		t.Fatal("could not connect to the daemon")
+	}
+
+	info, err := os.Stat(SocketPath())
+	if err != nil {
+		t.Fatalf("socket missing: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected socket mode 0600, got %o", info.Mode().Perm())
+	}
+
+	if _, err := c.Status(); err != nil {
+		t.Errorf("Status failed: %v", err)
+	}
+	if _, err := c.Start("not-a-client"); err == nil {
+		t.Error("expected starting an unknown client to fail")
+	}
+	if result, err := c.Stop("geth"); err != nil || result != nil {
+		t.Errorf("expected stopping a client that is not running to be a no-op, got %+v (%v)", result, err)
+	}
+
+	if err := NewServer(supervisor.DefaultOptions()).Serve(ctx, false); err == nil {
+		t.Error("expected a second daemon to refuse to start")
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("Serve returned an error: %v", err)
+	}
+	if _, err := os.Stat(SocketPath()); !os.IsNotExist(err) {
+		t.Error("expected the socket to be removed on shutdown")
+	}
+}
@@ -0,0 +1,156 @@
+// Package keystore stores Starknet wallet keys encrypted with a password, in
+// a JSON format modelled on Ethereum's V3 keystores: the key is encrypted with
+// AES-256-GCM under a key derived from the password with scrypt.
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// PasswordEnv is read instead of prompting for a keystore password, for
+// unattended use such as systemd units
+const PasswordEnv = "STARKNODE_KEYSTORE_PASSWORD"
+
+// ErrWrongPassword is returned when a keystore can't be decrypted
+var ErrWrongPassword = errors.New("wrong keystore password")
+
+// Keystore is an encrypted wallet key
+type Keystore struct {
+	Version   int        `json:"version"`
+	ID        string     `json:"id"`
+	Address   string     `json:"address"`
+	PublicKey string     `json:"public_key"`
+	Crypto    cryptoJSON `json:"crypto"`
+}
+
+type cryptoJSON struct {
+	Cipher       string       `json:"cipher"`
+	CipherText   string       `json:"ciphertext"`
+	CipherParams cipherParams `json:"cipherparams"`
+	KDF          string       `json:"kdf"`
+	KDFParams    scryptParams `json:"kdfparams"`
+}
+
+type cipherParams struct {
+	Nonce string `json:"nonce"`
+}
+
+type scryptParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// Path returns where the keystore of a named wallet is kept
+func Path(name string) string {
+	return filepath.Join(constants.KeystoreDir, name+".json")
+}
+
+// Encrypt encrypts the private key of a wallet with password
+func Encrypt(wallet types.Wallet, privateKey, password string) (*Keystore, error) {
+	if password == "" {
+		return nil, errors.New("the keystore password must not be empty")
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	crypto, err := encryptKey([]byte(privateKey), password)
+	if err != nil {
+		return nil, err
+	}
+	return &Keystore{
+		Version:   3,
+		ID:        id,
+		Address:   wallet.Address,
+		PublicKey: wallet.PublicKey,
+		Crypto:    crypto,
+	}, nil
+}
+
+// Decrypt returns the private key of a keystore
+func Decrypt(ks *Keystore, password string) (string, error) {
+	key, err := decryptKey(ks.Crypto, password)
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+// Save writes a keystore, readable only by the current user
+func Save(path string, ks *Keystore) error {
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads a keystore
+func Load(path string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("invalid keystore %s: %w", path, err)
+	}
+	if ks.Version != 3 || ks.Crypto.KDF != "scrypt" || ks.Crypto.Cipher != cipherName {
+		return nil, fmt.Errorf("unsupported keystore %s", path)
+	}
+	return &ks, nil
+}
+
+// PrivateKey decrypts the key of a keystore. The key of an unlocked session is
+// used if there is one, otherwise the password is read from PasswordEnv or
+// asked for on the terminal.
+func PrivateKey(path string) (string, error) {
+	ks, err := Load(path)
+	if err != nil {
+		return "", err
+	}
+	if key, ok := sessionKey(ks); ok {
+		return key, nil
+	}
+	password, err := ReadPassword(fmt.Sprintf("Password for keystore %s: ", filepath.Base(path)))
+	if err != nil {
+		return "", err
+	}
+	return Decrypt(ks, password)
+}
+
+// WalletKey returns the private key of a wallet, from its keystore or, for
+// wallets that were not migrated yet, from .starknode.env
+func WalletKey(wallet types.Wallet) (string, error) {
+	if wallet.Keystore != "" {
+		return PrivateKey(wallet.Keystore)
+	}
+	if IsPlaintextKey(wallet.PrivateKey) {
+		return wallet.PrivateKey, nil
+	}
+	return "", errors.New("the wallet has no keystore, run `starknode-kit wallet migrate` or import it again")
+}
+
+// IsPlaintextKey reports whether a configured private key is an actual key
+// rather than empty or an unresolved ${VAR} placeholder
+func IsPlaintextKey(key string) bool {
+	return key != "" && !strings.Contains(key, "${")
+}
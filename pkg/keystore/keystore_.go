@@ -0,0 +1,164 @@
+package keystore
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	cipherName = "aes-256-gcm"
+	keyLength  = 32
+	saltLength = 32
+)
+
+// scrypt cost parameters, the same as Ethereum's standard keystores
+var (
+	scryptN = 1 << 18
+	scryptR = 8
+	scryptP = 1
+)
+
+func encryptKey(plaintext []byte, password string) (cryptoJSON, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return cryptoJSON{}, err
+	}
+	params := scryptParams{N: scryptN, R: scryptR, P: scryptP, DKLen: keyLength, Salt: hex.EncodeToString(salt)}
+	aead, err := newAEAD(password, params)
+	if err != nil {
+		return cryptoJSON{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return cryptoJSON{}, err
+	}
+	return cryptoJSON{
+		Cipher:       cipherName,
+		CipherText:   hex.EncodeToString(aead.Seal(nil, nonce, plaintext, nil)),
+		CipherParams: cipherParams{Nonce: hex.EncodeToString(nonce)},
+		KDF:          "scrypt",
+		KDFParams:    params,
+	}, nil
+}
+
+func decryptKey(c cryptoJSON, password string) ([]byte, error) {
+	aead, err := newAEAD(password, c.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(c.CipherParams.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid keystore nonce")
+	}
+	ciphertext, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, errors.New("invalid keystore ciphertext")
+	}
+	// GCM authenticates the ciphertext, a wrong password fails here
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	return plaintext, nil
+}
+
+// newAEAD derives the encryption key from the password
+func newAEAD(password string, params scryptParams) (cipher.AEAD, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, errors.New("invalid keystore salt")
+	}
+	if params.DKLen != keyLength {
+		return nil, fmt.Errorf("unsupported keystore key length %d", params.DKLen)
+	}
+	key, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// newID returns a random UUID (version 4) identifying a keystore
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// ReadPassword reads a keystore password from PasswordEnv or, without it,
+// from the terminal
+func ReadPassword(prompt string) (string, error) {
+	if password, ok := os.LookupEnv(PasswordEnv); ok {
+		return password, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no terminal to ask for the keystore password, set %s or run `starknode-kit wallet unlock` first", PasswordEnv)
+	}
+	return readHidden(prompt)
+}
+
+// ReadSecret reads a secret such as a private key without echoing it, or
+// the first line of stdin when it is not a terminal
+func ReadSecret(prompt string) (string, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return readHidden(prompt)
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func readHidden(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// NewPassword asks for a new keystore password twice, or reads it from PasswordEnv
+func NewPassword() (string, error) {
+	if password, ok := os.LookupEnv(PasswordEnv); ok {
+		if password == "" {
+			return "", fmt.Errorf("%s is empty", PasswordEnv)
+		}
+		return password, nil
+	}
+	password, err := ReadPassword("New keystore password: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("the keystore password must not be empty")
+	}
+	confirm, err := ReadPassword("Repeat the password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirm {
+		return "", errors.New("the passwords do not match")
+	}
+	return password, nil
+}
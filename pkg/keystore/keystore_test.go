@@ -0,0 +1,177 @@
+package keystore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+const testKey = "0x1234abcd"
+
+func init() {
+	// Keep the tests fast, the cost is stored in each keystore
+	scryptN = 1 << 10
+}
+
+func writeTestKeystore(t *testing.T, password string) string {
+	t.Helper()
+	ks, err := Encrypt(types.Wallet{Address: "0xabc", PublicKey: "0xdef"}, testKey, password)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "keystore", "default.json")
+	if err := Save(path, ks); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	return path
+}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	path := writeTestKeystore(t, "correct horse")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected the keystore to be written: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("expected mode 0600, got %o", mode)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "1234abcd") {
+		t.Fatal("expected the private key to be encrypted")
+	}
+
+	ks, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if ks.Address != "0xabc" || ks.PublicKey != "0xdef" {
+		t.Errorf("unexpected keystore metadata %+v", ks)
+	}
+	key, err := Decrypt(ks, "correct horse")
+	if err != nil || key != testKey {
+		t.Fatalf("expected %s, got %q (%v)", testKey, key, err)
+	}
+	if _, err := Decrypt(ks, "wrong"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("expected ErrWrongPassword, got %v", err)
+	}
+}
+
+// useRuntimeDir points XDG_RUNTIME_DIR at a temporary directory treated as a tmpfs
+func useRuntimeDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	memoryBacked = func(string) bool { return true }
+	t.Cleanup(func() { memoryBacked = isMemoryBacked })
+}
+
+func TestUnlockCachesKey(t *testing.T) {
+	useRuntimeDir(t)
+	path := writeTestKeystore(t, "secret")
+
+	if _, err := Unlock(path, "wrong", time.Minute); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("expected ErrWrongPassword, got %v", err)
+	}
+	if _, err := Unlock(path, "secret", time.Minute); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	// No password is needed while the session is valid
+	key, err := PrivateKey(path)
+	if err != nil || key != testKey {
+		t.Fatalf("expected the cached key, got %q (%v)", key, err)
+	}
+
+	if err := Lock(path); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if _, ok := UnlockedUntil(path); ok {
+		t.Error("expected the wallet to be locked")
+	}
+	t.Setenv(PasswordEnv, "secret")
+	if key, err := PrivateKey(path); err != nil || key != testKey {
+		t.Errorf("expected the key decrypted with %s, got %q (%v)", PasswordEnv, key, err)
+	}
+}
+
+func TestExpiredSessionIsIgnored(t *testing.T) {
+	useRuntimeDir(t)
+	path := writeTestKeystore(t, "secret")
+
+	if _, err := Unlock(path, "secret", time.Nanosecond); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, ok := UnlockedUntil(path); ok {
+		t.Error("expected the session to have expired")
+	}
+}
+
+func TestUnlockNeedsRuntimeDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	memoryBacked = func(string) bool { return false }
+	t.Cleanup(func() { memoryBacked = isMemoryBacked })
+	path := writeTestKeystore(t, "secret")
+
+	if _, err := Unlock(path, "secret", time.Minute); !errors.Is(err, ErrNoRuntimeDir) {
+		t.Fatalf("expected ErrNoRuntimeDir, got %v", err)
+	}
+	if _, ok := UnlockedUntil(path); ok {
+		t.Error("expected nothing to be cached on disk")
+	}
+	if err := Lock(path); err != nil {
+		t.Errorf("expected Lock to succeed, got %v", err)
+	}
+}
+
+func TestMigrateEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".starknode.env")
+	godotenv.Write(map[string]string{
+		"STARKNET_WALLET":      "0xabc",
+		"STARKNET_PUBLIC_KEY":  "0xdef",
+		"STARKNET_PRIVATE_KEY": testKey,
+	}, envPath)
+	keystorePath := filepath.Join(dir, "keystore", "default.json")
+
+	if !HasPlaintextKey(envPath) {
+		t.Fatal("expected the plaintext key to be found")
+	}
+	migrated, err := MigrateEnvFile(envPath, keystorePath, "secret")
+	if err != nil || !migrated {
+		t.Fatalf("expected the key to be migrated, got %v (%v)", migrated, err)
+	}
+
+	env, _ := godotenv.Read(envPath)
+	if _, ok := env["STARKNET_PRIVATE_KEY"]; ok {
+		t.Error("expected the key to be removed from the env file")
+	}
+	if env["STARKNET_WALLET"] != "0xabc" {
+		t.Error("expected the other variables to be kept")
+	}
+	ks, err := Load(keystorePath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if key, err := Decrypt(ks, "secret"); err != nil || key != testKey {
+		t.Errorf("expected the migrated key, got %q (%v)", key, err)
+	}
+
+	// Running it again is a no-op
+	if migrated, err := MigrateEnvFile(envPath, keystorePath, "secret"); err != nil || migrated {
+		t.Errorf("expected nothing to migrate, got %v (%v)", migrated, err)
+	}
+}
+
+func TestWalletKeyOfUnmigratedWallet(t *testing.T) {
+	if key, err := WalletKey(types.Wallet{PrivateKey: testKey}); err != nil || key != testKey {
+		t.Errorf("expected the plaintext key, got %q (%v)", key, err)
+	}
+	if _, err := WalletKey(types.Wallet{PrivateKey: "${STARKNET_PRIVATE_KEY}"}); err == nil {
+		t.Error("expected an unresolved placeholder to be rejected")
+	}
+}
@@ -0,0 +1,57 @@
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// privateKeyEnv is the variable older versions stored the wallet key in
+const privateKeyEnv = "STARKNET_PRIVATE_KEY"
+
+// HasPlaintextKey reports whether an env file still holds a wallet private key
+func HasPlaintextKey(envPath string) bool {
+	env, err := godotenv.Read(envPath)
+	return err == nil && env[privateKeyEnv] != ""
+}
+
+// MigrateEnvFile moves the wallet private key of an env file written by older
+// versions into a keystore at keystorePath and removes it from the env file.
+// The other variables stay. It reports false if there was no key to migrate.
+func MigrateEnvFile(envPath, keystorePath, password string) (bool, error) {
+	env, err := godotenv.Read(envPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", envPath, err)
+	}
+	key := env[privateKeyEnv]
+	if key == "" {
+		return false, nil
+	}
+
+	wallet := types.Wallet{Address: env["STARKNET_WALLET"], PublicKey: env["STARKNET_PUBLIC_KEY"]}
+	ks, err := Encrypt(wallet, key, password)
+	if err != nil {
+		return false, err
+	}
+	// Write the keystore first so the key is never lost
+	if err := Save(keystorePath, ks); err != nil {
+		return false, err
+	}
+
+	delete(env, privateKeyEnv)
+	if err := godotenv.Write(env, envPath); err != nil {
+		return false, fmt.Errorf("keystore written to %s but failed to remove the key from %s: %w", keystorePath, envPath, err)
+	}
+	if err := os.Chmod(envPath, 0o600); err != nil {
+		return false, err
+	}
+	// LoadConfig already exported the env file into this process
+	os.Unsetenv(privateKeyEnv)
+	return true, nil
+}
@@ -0,0 +1,169 @@
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultSessionTTL is how long `wallet unlock` keeps a key unlocked by default
+const DefaultSessionTTL = 15 * time.Minute
+
+// ErrNoRuntimeDir is returned when there is nowhere to keep a secret off disk
+var ErrNoRuntimeDir = errors.New("XDG_RUNTIME_DIR is not set to a tmpfs, secrets can't be kept off disk")
+
+var memoryBacked = isMemoryBacked
+
+// isMemoryBacked reports whether a directory is on a filesystem that lives in RAM
+func isMemoryBacked(dir string) bool {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(dir, &fs); err != nil {
+		return false
+	}
+	return fs.Type == unix.TMPFS_MAGIC || fs.Type == unix.RAMFS_MAGIC
+}
+
+// RuntimeDir returns starknode-kit's directory in the user's runtime directory,
+// a tmpfs that is cleared on logout and never written to disk. The directory
+// is not created.
+func RuntimeDir() (string, error) {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" || !memoryBacked(dir) {
+		return "", ErrNoRuntimeDir
+	}
+	return filepath.Join(dir, "starknode-kit"), nil
+}
+
+// session is an unlocked keystore key. Sessions only live in RuntimeDir;
+// without one nothing is cached and the password is asked every time.
+type session struct {
+	PrivateKey string    `json:"private_key"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Unlock decrypts a keystore and keeps its key available to later commands
+// for ttl, so they don't ask for the password again
+func Unlock(path, password string, ttl time.Duration) (time.Time, error) {
+	ks, err := Load(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	key, err := Decrypt(ks, password)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cacheSession(ks, key, ttl)
+}
+
+// Lock forgets the unlocked key of a keystore
+func Lock(path string) error {
+	ks, err := Load(path)
+	if err != nil {
+		return err
+	}
+	file, err := sessionPath(ks)
+	if errors.Is(err, ErrNoRuntimeDir) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// UnlockedUntil returns when the session of a keystore expires, false if it is locked
+func UnlockedUntil(path string) (time.Time, bool) {
+	ks, err := Load(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	s, ok := loadSession(ks)
+	if !ok {
+		return time.Time{}, false
+	}
+	return s.ExpiresAt, true
+}
+
+func cacheSession(ks *Keystore, key string, ttl time.Duration) (time.Time, error) {
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	file, err := sessionPath(ks)
+	if err != nil {
+		return time.Time{}, err
+	}
+	s := session{PrivateKey: key, ExpiresAt: time.Now().Add(ttl)}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return time.Time{}, fmt.Errorf("failed to cache unlocked key: %w", err)
+	}
+	return s.ExpiresAt, os.Rename(tmp, file)
+}
+
+func sessionKey(ks *Keystore) (string, bool) {
+	s, ok := loadSession(ks)
+	if !ok {
+		return "", false
+	}
+	return s.PrivateKey, true
+}
+
+// loadSession returns the unexpired session of a keystore, removing expired ones
+func loadSession(ks *Keystore) (session, bool) {
+	file, err := sessionPath(ks)
+	if err != nil {
+		return session{}, false
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return session{}, false
+	}
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil || time.Now().After(s.ExpiresAt) {
+		os.Remove(file)
+		return session{}, false
+	}
+	return s, true
+}
+
+// sessionPath returns the session file of a keystore, creating its private directory
+func sessionPath(ks *Keystore) (string, error) {
+	dir, err := PrivateRuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ks.ID+".session"), nil
+}
+
+// PrivateRuntimeDir creates RuntimeDir and checks that only this user can use it
+func PrivateRuntimeDir() (string, error) {
+	dir, err := RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create runtime directory: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !info.IsDir() || !ok || int(stat.Uid) != os.Getuid() || info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("runtime directory %s is not private to this user", dir)
+	}
+	return dir, nil
+}
@@ -0,0 +1,157 @@
+package logrotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+const (
+	DefaultMaxSizeMB  = 100
+	DefaultMaxAge     = 7 * 24 * time.Hour
+	DefaultMaxBackups = 10
+)
+
+// Options are the resolved rotation limits of a Writer
+type Options struct {
+	MaxSize    int64
+	MaxAge     time.Duration
+	MaxBackups int
+	Compress   bool
+}
+
+// Writer writes a client's output to <dir>/<name>.log, rotating it to
+// <name>-<timestamp>.log(.gz) when it grows too large or too old
+type Writer struct {
+	dir  string
+	name string
+	opts Options
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// compressing tracks gzip jobs still running for rotated segments
+	compressing sync.WaitGroup
+}
+
+// OptionsFromConfig applies the defaults to unset fields of a client's log config
+func OptionsFromConfig(cfg types.LogConfig) Options {
+	opts := Options{
+		MaxSize:    DefaultMaxSizeMB * 1024 * 1024,
+		MaxAge:     DefaultMaxAge,
+		MaxBackups: DefaultMaxBackups,
+		Compress:   true,
+	}
+	if cfg.MaxSizeMB > 0 {
+		opts.MaxSize = int64(cfg.MaxSizeMB) * 1024 * 1024
+	}
+	if cfg.MaxAge > 0 {
+		opts.MaxAge = cfg.MaxAge
+	}
+	if cfg.MaxBackups > 0 {
+		opts.MaxBackups = cfg.MaxBackups
+	}
+	if cfg.Compress != nil {
+		opts.Compress = *cfg.Compress
+	}
+	return opts
+}
+
+// ActivePath returns the path of the segment a client is currently writing to
+func ActivePath(dir, name string) string {
+	return filepath.Join(dir, name+".log")
+}
+
+// Backups returns the rotated segments of a client's log, oldest first
+func Backups(dir, name string) []string {
+	plain, _ := filepath.Glob(filepath.Join(dir, name+"-*.log"))
+	compressed, _ := filepath.Glob(filepath.Join(dir, name+"-*.log.gz"))
+	backups := append(plain, compressed...)
+	sort.Strings(backups)
+	return backups
+}
+
+// LatestLogFile returns the file a client is logging to: the active segment, or
+// the newest plain .log file for clients started before rotation was introduced.
+// Compressed segments are never returned.
+func LatestLogFile(dir, name string) (string, error) {
+	if _, err := os.Stat(ActivePath(dir, name)); err == nil {
+		return ActivePath(dir, name), nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		return "", err
+	}
+	var newest string
+	var newestTime time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestTime) {
+			newest = file
+			newestTime = info.ModTime()
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no log files found in %s", dir)
+	}
+	return newest, nil
+}
+
+// New opens the active segment of a client's log, appending to it if it exists
+func New(dir, name string, opts Options) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	w := &Writer{dir: dir, name: name, opts: opts}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	if w.size > 0 && w.needsRotation(0) {
+		if err := w.rotate(); err != nil {
+			w.file.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Path returns the path of the active segment
+func (w *Writer) Path() string {
+	return ActivePath(w.dir, w.name)
+}
+
+// Write appends p to the active segment, rotating first if it would exceed the
+// limits. A single write is never split, so a segment holds at least one write.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.needsRotation(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the active segment and waits for pending compression
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	err := w.file.Close()
+	w.mu.Unlock()
+	w.compressing.Wait()
+	return err
+}
@@ -0,0 +1,107 @@
+package logrotate
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// backupTimeFormat sorts lexically in chronological order
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.Path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = time.Now()
+	if w.size > 0 {
+		// Appending to a segment left by a previous run, its age counts from its last write
+		w.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (w *Writer) needsRotation(incoming int64) bool {
+	if w.opts.MaxSize > 0 && w.size+incoming > w.opts.MaxSize {
+		return true
+	}
+	return w.opts.MaxAge > 0 && time.Since(w.openedAt) > w.opts.MaxAge
+}
+
+// rotate moves the active segment aside, opens a fresh one and compresses and
+// prunes old segments in the background so the client is never blocked on it
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	backup := filepath.Join(w.dir, fmt.Sprintf("%s-%s.log", w.name, time.Now().Format(backupTimeFormat)))
+	if err := os.Rename(w.Path(), backup); err != nil {
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.compressing.Add(1)
+	go func() {
+		defer w.compressing.Done()
+		if w.opts.Compress {
+			compressFile(backup)
+		}
+		w.prune()
+	}()
+	return nil
+}
+
+// prune removes the oldest rotated segments beyond MaxBackups
+func (w *Writer) prune() {
+	backups := Backups(w.dir, w.name)
+	if w.opts.MaxBackups <= 0 || len(backups) <= w.opts.MaxBackups {
+		return
+	}
+	for _, old := range backups[:len(backups)-w.opts.MaxBackups] {
+		os.Remove(old)
+	}
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,93 @@
+package logrotate
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	w, err := New(dir, "geth", Options{MaxSize: 100, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	line := strings.Repeat("x", 59) + "\n"
+	for range 10 {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		// Keep backup timestamps distinct
+		time.Sleep(2 * time.Millisecond)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	backups := Backups(dir, "geth")
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups to be kept, got %v", backups)
+	}
+	for _, backup := range backups {
+		if !strings.HasSuffix(backup, ".log.gz") {
+			t.Errorf("expected backup %s to be compressed", backup)
+		}
+	}
+
+	info, err := os.Stat(ActivePath(dir, "geth"))
+	if err != nil {
+		t.Fatalf("active segment missing: %v", err)
+	}
+	if info.Size() > 100 {
+		t.Errorf("expected active segment to stay under the size limit, got %d bytes", info.Size())
+	}
+
+	latest, err := LatestLogFile(dir, "geth")
+	if err != nil || latest != ActivePath(dir, "geth") {
+		t.Errorf("expected LatestLogFile to return the active segment, got %s (%v)", latest, err)
+	}
+}
+
+func TestRotateByAgeOnOpen(t *testing.T) {
+	dir := t.TempDir()
+	path := ActivePath(dir, "juno")
+	if err := os.WriteFile(path, []byte("old run\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New(dir, "juno", Options{MaxAge: 24 * time.Hour, MaxBackups: 5})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	w.Write([]byte("new run\n"))
+	w.Close()
+
+	backups := Backups(dir, "juno")
+	if len(backups) != 1 {
+		t.Fatalf("expected the stale segment to be rotated, got %v", backups)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "new run\n" {
+		t.Errorf("expected a fresh active segment, got %q", data)
+	}
+}
+
+func TestLatestLogFileLegacy(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(dir+"/geth_2025-01-01_00-00-00.log", []byte("a"), 0o644)
+	os.WriteFile(dir+"/geth-2025-01-01T00-00-00.000.log.gz", []byte("b"), 0o644)
+
+	latest, err := LatestLogFile(dir, "geth")
+	if err != nil {
+		t.Fatalf("LatestLogFile failed: %v", err)
+	}
+	if !strings.HasSuffix(latest, "geth_2025-01-01_00-00-00.log") {
+		t.Errorf("expected the legacy log file, got %s", latest)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/logrotate"
 	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 
@@ -32,10 +33,11 @@ func GetEthereumMetrics() t.EthereumMetrics {
 	}
 
 	client := &http.Client{Timeout: 2 * time.Second}
+	rpcURL := config.ExecutionCientSettings.Ports().HTTPURL()
 
 	// Get current block number
 	blockPayload := `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`
-	resp, err := client.Post("http://localhost:8545", "application/json", strings.NewReader(blockPayload))
+	resp, err := client.Post(rpcURL, "application/json", strings.NewReader(blockPayload))
 	if err == nil {
 		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
@@ -51,7 +53,7 @@ func GetEthereumMetrics() t.EthereumMetrics {
 
 	// Get gas price
 	gasPricePayload := `{"jsonrpc":"2.0","method":"eth_gasPrice","params":[],"id":3}`
-	gasResp, err := client.Post("http://localhost:8545", "application/json", strings.NewReader(gasPricePayload))
+	gasResp, err := client.Post(rpcURL, "application/json", strings.NewReader(gasPricePayload))
 	if err == nil {
 		defer gasResp.Body.Close()
 		gasBody, _ := io.ReadAll(gasResp.Body)
@@ -69,7 +71,7 @@ func GetEthereumMetrics() t.EthereumMetrics {
 
 	// Get peer count
 	peerPayload := `{"jsonrpc":"2.0","method":"net_peerCount","params":[],"id":2}`
-	peerResp, err := client.Post("http://localhost:8545", "application/json", strings.NewReader(peerPayload))
+	peerResp, err := client.Post(rpcURL, "application/json", strings.NewReader(peerPayload))
 	if err == nil {
 		defer peerResp.Body.Close()
 		peerBody, _ := io.ReadAll(peerResp.Body)
@@ -91,34 +93,16 @@ func GetLatestLogs(clientName string, lines int) []string {
 	logDir := filepath.Join(constants.InstallClientsDir, clientName, "logs")
 
 	// NOTE minor fix
-	if clientName == "juno" {
+	if clientName == "juno" || clientName == "starknet-staking-v2" {
 		logDir = filepath.Join(constants.InstallStarknetDir, clientName, "logs")
 	}
 
-	// Find the most recent log file
-	files, err := filepath.Glob(filepath.Join(logDir, "*.log"))
-	if err != nil || len(files) == 0 {
+	// Find the segment the client is currently writing to
+	newestFile, err := logrotate.LatestLogFile(logDir, clientName)
+	if err != nil {
 		return []string{fmt.Sprintf("No log files found for %s", clientName)}
 	}
 
-	// Get the most recent file
-	var newestFile string
-	var newestTime time.Time
-	for _, file := range files {
-		info, err := os.Stat(file)
-		if err != nil {
-			continue
-		}
-		if info.ModTime().After(newestTime) {
-			newestTime = info.ModTime()
-			newestFile = file
-		}
-	}
-
-	if newestFile == "" {
-		return []string{"No recent log files found"}
-	}
-
 	// Read the last few lines
 	content, err := os.ReadFile(newestFile)
 	if err != nil {
@@ -3,9 +3,14 @@ package monitoring
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 
 	"github.com/gdamore/tcell/v2"
@@ -176,35 +181,60 @@ func (m *MonitorApp) createHelpBox() *tview.TextView {
 	return helpBox
 }
 
-// Enhanced client control methods
+// restartClients restarts every running client with its current config,
+// dependencies first, through the daemon when it is running
 func (m *MonitorApp) restartClients() {
 	m.updateStatusBar("[yellow]⚠️  Restarting all clients...[white]")
 
-	clients := utils.GetRunningClients()
-	for _, client := range clients {
-		if client.PID > 0 {
-			m.updateStatusBar(fmt.Sprintf("[red]🔄 Stopping %s (PID: %d)...[white]", client.Name, client.PID))
-			time.Sleep(500 * time.Millisecond) // Visual feedback
+	go func() {
+		cfg, _ := utils.LoadConfig()
+		records, _ := process.ListRecords()
+		names := make([]string, 0, len(records))
+		for _, record := range records {
+			names = append(names, record.Name)
 		}
-		m.updateStatusBar(fmt.Sprintf("[green]▶️  Starting %s...[white]", client.Name))
-		time.Sleep(500 * time.Millisecond) // Visual feedback
-	}
 
-	m.updateStatusBar("[green]✅ All clients restarted successfully[white]")
+		for _, name := range slices.Backward(clients.StopOrder(cfg, names)) {
+			m.updateStatusBar(fmt.Sprintf("[yellow]🔄 Restarting %s...[white]", name))
+			pid, err := daemon.RestartClient(cfg, types.ClientType(name))
+			if err != nil {
+				m.updateStatusBar(fmt.Sprintf("[red]❌ Failed to restart %s: %v[white]", name, err))
+				return
+			}
+			m.updateStatusBar(fmt.Sprintf("[green]▶️  %s restarted (PID: %d)[white]", name, pid))
+		}
+
+		m.updateStatusBar("[green]✅ All clients restarted successfully[white]")
+	}()
 }
 
+// stopClients stops every running client in reverse dependency order, through
+// the daemon when it is running so managed clients are not restarted
 func (m *MonitorApp) stopClients() {
 	m.updateStatusBar("[yellow]⚠️  Stopping all clients...[white]")
 
-	clients := utils.GetRunningClients()
-	for _, client := range clients {
-		if client.PID > 0 {
-			m.updateStatusBar(fmt.Sprintf("[red]⏹️  Stopping %s (PID: %d)...[white]", client.Name, client.PID))
-			time.Sleep(300 * time.Millisecond)
+	go func() {
+		cfg, _ := utils.LoadConfig()
+		records, _ := process.ListRecords()
+		names := make([]string, 0, len(records))
+		for _, record := range records {
+			names = append(names, record.Name)
+		}
+
+		for _, name := range clients.StopOrder(cfg, names) {
+			info := process.GetProcessInfo(name)
+			if info == nil {
+				continue
+			}
+			m.updateStatusBar(fmt.Sprintf("[red]⏹️  Stopping %s (PID: %d)...[white]", name, info.PID))
+			if _, err := daemon.StopClient(cfg, types.ClientType(name)); err != nil {
+				m.updateStatusBar(fmt.Sprintf("[red]❌ Failed to stop %s: %v[white]", name, err))
+				return
+			}
 		}
-	}
 
-	m.updateStatusBar("[red]🔴 All clients stopped[white]")
+		m.updateStatusBar("[red]🔴 All clients stopped[white]")
+	}()
 }
 
 func (m *MonitorApp) toggleLogs() {
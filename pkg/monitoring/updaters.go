@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/thebuidl-grid/starknode-kit/pkg/daemon"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
 	"github.com/thebuidl-grid/starknode-kit/pkg/stats"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
@@ -335,7 +338,7 @@ func (m *MonitorApp) updateStatusBox(ctx context.Context) {
 			// Add some dynamic information
 			config, _ := utils.LoadConfig()
 			currentTime := time.Now()
-			ethStatus := utils.GetGethSyncStatus()
+			ethStatus, l2Status, viaDaemon := syncStatus(config)
 			currentStrkBlock := ethStatus.CurrentBlock
 			peers := ethStatus.PeersCount
 			netowrk := config.Network
@@ -345,26 +348,82 @@ func (m *MonitorApp) updateStatusBox(ctx context.Context) {
 			l1statusContent += fmt.Sprintf("Peers: [green]%d[white]\n", peers)
 			l1statusContent += fmt.Sprintf("Syncing: [green]%t[white]\n", isSyncing)
 
-			l2Status := utils.GetJunoMetrics(netowrk)
 			l2statusContent := fmt.Sprintf("Current Block: [green]%d[white]\n", l2Status.CurrentBlock)
 			l2statusContent += fmt.Sprintf("Syncing: [green]%t[white]\n", l2Status.IsSyncing)
 			l2statusContent += fmt.Sprintf("Syncing Percent: [green]%.2f[white]\n", l2Status.SyncPercent)
 
 			networkChanContent := fmt.Sprintf("Network: [green]%s\n[white]time: %s", netowrk, currentTime.Format("15:04:05"))
+			if viaDaemon {
+				networkChanContent += "\nDaemon: [green]running[white]"
+			}
 
-			// Send to status channel
-			select {
-			case m.StatusChan <- l1statusContent:
-			case m.JunoStatusChan <- l2statusContent:
-			case m.NetworkChan <- networkChanContent:
-			default:
-				// Channel full, skip update
+			usage := sampleClientResources([]string{
+				string(config.ExecutionCientSettings.Name),
+				string(config.ConsensusCientSettings.Name),
+				string(types.ClientJuno),
+				string(types.ClientStarkValidator),
+			})
+			l1statusContent += usage[string(config.ExecutionCientSettings.Name)]
+			l1statusContent += usage[string(config.ConsensusCientSettings.Name)]
+			l2statusContent += usage[string(types.ClientJuno)]
+			l2statusContent += usage[string(types.ClientStarkValidator)]
+
+			// Send to status channels, skipping any that are full
+			for ch, content := range map[chan string]string{
+				m.StatusChan:     l1statusContent,
+				m.JunoStatusChan: l2statusContent,
+				m.NetworkChan:    networkChanContent,
+			} {
+				select {
+				case ch <- content:
+				default:
+				}
 			}
 
 		}
 	}
 }
 
+// syncStatus returns the L1 and L2 sync progress, from the daemon when it is running
+func syncStatus(cfg types.StarkNodeKitConfig) (types.SyncInfo, types.EthereumMetrics, bool) {
+	if c := daemon.Dial(); c != nil {
+		if status, err := c.Sync(); err == nil {
+			return status.Execution, status.Starknet, true
+		}
+	}
+	return utils.GetGethSyncStatus(cfg.ExecutionCientSettings.Ports().HTTPURL()), utils.GetJunoMetrics(cfg.Network, cfg.JunoConfig.Ports().HTTPURL()), false
+}
+
+// sampleClientResources measures the CPU, memory and open files of every
+// running client concurrently and returns a status line per client name
+func sampleClientResources(names []string) map[string]string {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		lines = make(map[string]string, len(names))
+	)
+	for _, name := range names {
+		info := process.GetProcessUsage(name)
+		if name == "" || info == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if cpu, err := process.MeasureCPU(info.PID, time.Second); err == nil {
+				info.CPUUsage = cpu
+			}
+			line := fmt.Sprintf("%s: [green]%.1f%%[white] CPU, [green]%s[white], %d FDs\n",
+				name, info.CPUUsage, formatBytes(info.MemUsage), info.OpenFDs)
+			mu.Lock()
+			lines[name] = line
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return lines
+}
+
 // Update chain info box (matching chainInfoBox.js populateChainInfoBox)
 func (m *MonitorApp) updateChainInfoBox(ctx context.Context) {
 	ticker := time.NewTicker(3 * time.Second)
@@ -479,13 +538,15 @@ func (m *MonitorApp) updateRPCInfo(ctx context.Context) {
 			content := "[yellow][bold]RPC STATUS[white]\n"
 			content += strings.Repeat("-", 15) + "\n"
 
+			config, _ := utils.LoadConfig()
+
 			// Execution RPC
-			execRPCURL := "http://localhost:8545"
+			execRPCURL := config.ExecutionCientSettings.Ports().HTTPURL()
 			execStatus, _ := utils.CheckRPCStatus(execRPCURL, "web3_clientVersion")
 			content += fmt.Sprintf("[blue]Execution:[white]\n%s\n[dim]%s[white]\n\n", execStatus, execRPCURL)
 
 			// Consensus RPC
-			consRPCURL := "http://localhost:5054"
+			consRPCURL := config.ConsensusCientSettings.Ports().HTTPURL()
 			consStatus, _ := utils.CheckRPCStatus(consRPCURL, "eth/v1/node/health") // Use a valid consensus-layer method
 			content += fmt.Sprintf("[blue]Consensus:[white]\n%s\n[dim]%s[white]\n", consStatus, consRPCURL)
 
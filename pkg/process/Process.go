@@ -1,42 +1,250 @@
 package process
 
 import (
+	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/thebuidl-grid/starknode-kit/pkg/logrotate"
 	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
 )
 
 func IsProcessRunning(pid int) bool {
 	err := syscall.Kill(pid, 0)
-	return err != nil
+	return err == nil
 }
 
-func StartClient(name, command string, logPath io.Writer, args ...string) error {
+// LogWriterCommand is the hidden starknode-kit subcommand that copies a
+// detached client's output into its rotating log files
+const LogWriterCommand = "log-writer"
 
-	cmd := exec.Command(command, args...)
+// LaunchedClient is a client started by LaunchClient whose output is written
+// by this process
+type LaunchedClient struct {
+	Cmd     *exec.Cmd
+	LogFile string
 
+	logs  *logrotate.Writer
+	files []t.ClientFile
+}
+
+// Wait waits for the client to exit, flushes its remaining output and removes
+// its files in case it exited before reading them
+func (c *LaunchedClient) Wait() error {
+	err := c.Cmd.Wait()
+	c.logs.Close()
+	RemoveClientFiles(c.files)
+	return err
+}
+
+// StartClient launches a client in its own session and returns once it is running.
+// Its output goes through a detached log writer so rotation keeps working after
+// starknode-kit exits. The resource limits of the spec are applied before it
+// returns.
+func StartClient(spec t.ClientSpec) error {
+	if err := ValidateResources(spec.Resources); err != nil {
+		return fmt.Errorf("invalid resources of %s: %w", spec.Name, err)
+	}
+	if err := WriteClientFiles(spec.Files); err != nil {
+		return err
+	}
+	defer RemoveClientFiles(spec.Files)
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	// The children keep their own ends of the pipe once started
+	defer reader.Close()
+	defer writer.Close()
+
+	logWriter, err := logWriterCmd(spec)
+	if err != nil {
+		return fmt.Errorf("failed to start log writer: %w", err)
+	}
+	logWriter.Stdin = reader
+	if err := logWriter.Start(); err != nil {
+		return fmt.Errorf("failed to start log writer: %w", err)
+	}
+	logWriter.Process.Release()
+
+	cmd := clientCmd(spec)
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setsid: true,
 	}
-	cmd.Stdout = logPath
-	cmd.Stderr = logPath
-
-	err := cmd.Start()
+	cgroup, err := prepareCgroup(spec.Name, spec.Resources, cmd.SysProcAttr)
 	if err != nil {
 		return err
 	}
+	defer cgroup.Close()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	// If the client fails to start the log writer sees EOF and exits
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if err := applyLimits(cmd.Process.Pid, spec.Resources); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+	if err := registerClient(spec.Name, cmd, logrotate.ActivePath(spec.LogDir, spec.Name)); err != nil {
+		// An unregistered client could never be found or stopped again
+		cmd.Process.Kill()
+		cmd.Wait()
+		return err
+	}
+	// Nobody is left to remove the files once starknode-kit exits
+	AwaitClientFiles(spec.Files, cmd.Process.Pid)
+	return nil
+}
+
+// LaunchClient launches a client in its own session for callers that stay
+// alive and wait on it (e.g. the supervisor). Its output is rotated in-process.
+func LaunchClient(spec t.ClientSpec) (*LaunchedClient, error) {
+	return launchClient(spec, nil)
+}
+
+// LaunchAttached is like LaunchClient but also copies the client's output to
+// out, for running clients in the foreground
+func LaunchAttached(spec t.ClientSpec, out io.Writer) (*LaunchedClient, error) {
+	return launchClient(spec, out)
+}
+
+func launchClient(spec t.ClientSpec, out io.Writer) (*LaunchedClient, error) {
+	if err := ValidateResources(spec.Resources); err != nil {
+		return nil, fmt.Errorf("invalid resources of %s: %w", spec.Name, err)
+	}
+	if err := WriteClientFiles(spec.Files); err != nil {
+		return nil, err
+	}
+	logs, err := logrotate.New(spec.LogDir, spec.Name, logrotate.OptionsFromConfig(spec.Logs))
+	if err != nil {
+		RemoveClientFiles(spec.Files)
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+
+	cmd := clientCmd(spec)
+	// Its own session keeps terminal signals away from the client, the caller
+	// decides when and in which order clients are stopped
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid: true,
+	}
+	cgroup, err := prepareCgroup(spec.Name, spec.Resources, cmd.SysProcAttr)
+	if err != nil {
+		logs.Close()
+		RemoveClientFiles(spec.Files)
+		return nil, err
+	}
+	defer cgroup.Close()
+	cmd.Stdout = logs
+	if out != nil {
+		cmd.Stdout = io.MultiWriter(logs, out)
+	}
+	cmd.Stderr = cmd.Stdout
+	// Don't hang on children that inherited the output pipe and outlive the client
+	cmd.WaitDelay = 5 * time.Second
+
+	client := &LaunchedClient{Cmd: cmd, LogFile: logs.Path(), logs: logs, files: spec.Files}
+	if err := cmd.Start(); err != nil {
+		logs.Close()
+		RemoveClientFiles(spec.Files)
+		return nil, err
+	}
+	if err := applyLimits(cmd.Process.Pid, spec.Resources); err != nil {
+		cmd.Process.Kill()
+		client.Wait()
+		return nil, err
+	}
+	if err := registerClient(spec.Name, cmd, logs.Path()); err != nil {
+		cmd.Process.Kill()
+		client.Wait()
+		return nil, err
+	}
+	go AwaitClientFiles(spec.Files, cmd.Process.Pid)
+	return client, nil
+}
+
+// clientCmd builds the command of a client with its extra environment
+func clientCmd(spec t.ClientSpec) *exec.Cmd {
+	cmd := exec.Command(spec.Command, spec.Args...)
+	if len(spec.Env) > 0 {
+		cmd.Env = append(os.Environ(), spec.Env...)
+	}
+	return cmd
+}
+
+// registerClient records a freshly started client in the state registry
+func registerClient(name string, cmd *exec.Cmd, logFile string) error {
+	pid := cmd.Process.Pid
+	ticks, err := getProcessStartTicks(pid)
+	if err != nil {
+		return fmt.Errorf("failed to read start time of %s (PID %d): %w", name, pid, err)
+	}
+	startedAt, err := ticksToTime(ticks)
+	if err != nil {
+		startedAt = time.Now()
+	}
 
+	err = SaveRecord(t.ProcessRecord{
+		Name:       name,
+		PID:        pid,
+		StartTicks: ticks,
+		StartedAt:  startedAt,
+		Binary:     cmd.Path,
+		Args:       cmd.Args[1:],
+		LogFile:    logFile,
+		ConfigHash: ConfigHash(cmd.Path, cmd.Args[1:]),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register %s (PID %d): %w", name, pid, err)
+	}
 	return nil
 }
 
-func StopClient(pid int) error {
-	return stopProcess(pid)
+// StopClient sends SIGTERM to a client and waits up to timeout for it to exit,
+// escalating to SIGKILL if it is still running. It only returns once the
+// process is gone.
+func StopClient(pid int, timeout time.Duration) (t.StopResult, error) {
+	return stopProcess(pid, timeout)
 }
 
 func GetProcessInfo(p string) *t.ProcessInfo {
 	return getProcessInfo(strings.ToLower(p))
 }
 
+// GetProcessUsage is GetProcessInfo with the resource usage of the client's
+// process tree and its limits filled in. It walks /proc, so only status
+// displays should call it.
+func GetProcessUsage(p string) *t.ProcessInfo {
+	info := getProcessInfo(strings.ToLower(p))
+	if info != nil {
+		fillResourceUsage(info)
+		fillLimits(info)
+	}
+	return info
+}
+
+// ReapExitCode collects the exit code of a client this process started and
+// that has exited. It reports false if the client is still running or was
+// started by another process, which then owns its exit status.
+func ReapExitCode(pid int) (int, bool) {
+	var status syscall.WaitStatus
+	reaped, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil)
+	if err != nil || reaped != pid {
+		return 0, false
+	}
+	if status.Signaled() {
+		return 128 + int(status.Signal()), true
+	}
+	return status.ExitStatus(), true
+}
+
+// TailLog returns up to n of the last non-empty lines of a log file
+func TailLog(path string, n int) ([]string, error) {
+	return tailFile(path, n)
+}
@@ -0,0 +1,110 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"golang.org/x/sys/unix"
+)
+
+// ClientFilesCommand is the hidden starknode-kit subcommand that writes and
+// removes a client's files around a systemd launch
+const ClientFilesCommand = "client-files"
+
+// clientFileTimeout is how long a client has to read its files before they
+// are removed anyway
+var clientFileTimeout = 30 * time.Second
+
+var onTmpfs = isTmpfs
+
+// isTmpfs reports whether a directory is kept in RAM, so files in it never
+// reach the disk
+func isTmpfs(dir string) bool {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(dir, &fs); err != nil {
+		return false
+	}
+	return fs.Type == unix.TMPFS_MAGIC || fs.Type == unix.RAMFS_MAGIC
+}
+
+// WriteClientFiles writes the files a client reads at startup. They can hold
+// secrets, so they are only readable by the user running the client and are
+// only written to a tmpfs.
+func WriteClientFiles(files []t.ClientFile) error {
+	for _, file := range files {
+		dir := filepath.Dir(file.Path)
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to create directory of %s: %w", file.Path, err)
+		}
+		if !onTmpfs(dir) {
+			return fmt.Errorf("refusing to write %s, %s is not a tmpfs (is XDG_RUNTIME_DIR set?)", file.Path, dir)
+		}
+		data, err := file.Data()
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", file.Path, err)
+		}
+		// Write a new file so an existing one with looser permissions is replaced
+		tmp := file.Path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+		if err := os.Chmod(tmp, 0o600); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+		// Reset the access time so fileRead can tell when the client opened it
+		if err := os.Chtimes(tmp, time.Unix(0, 0), time.Time{}); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+		if err := os.Rename(tmp, file.Path); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
+
+// RemoveClientFiles removes the files written for a client
+func RemoveClientFiles(files []t.ClientFile) {
+	for _, file := range files {
+		os.Remove(file.Path)
+	}
+}
+
+// AwaitClientFiles removes a client's files once it has read all of them, has
+// exited or clientFileTimeout has passed, whichever comes first
+func AwaitClientFiles(files []t.ClientFile, pid int) {
+	defer RemoveClientFiles(files)
+	deadline := time.Now().Add(clientFileTimeout)
+	for time.Now().Before(deadline) {
+		if allRead(files) || !IsProcessRunning(pid) || getProcessState(pid) == "Z" {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func allRead(files []t.ClientFile) bool {
+	for _, file := range files {
+		if !fileRead(file.Path) {
+			return false
+		}
+	}
+	return true
+}
+
+// fileRead reports whether a file written by WriteClientFiles was read since.
+// Reading it moves its access time away from the epoch, see relatime.
+func fileRead(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && (stat.Atim.Sec != 0 || stat.Atim.Nsec != 0)
+}
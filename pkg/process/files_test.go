@@ -0,0 +1,101 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestWriteClientFilesIsPrivate(t *testing.T) {
+	useTmpfs(t)
+	path := filepath.Join(t.TempDir(), "secrets", "validator.json")
+	// A leftover file with loose permissions must not keep them
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte("old"), 0o644)
+
+	if err := WriteClientFiles([]types.ClientFile{{Path: path, Data: func() ([]byte, error) { return []byte(`{"signer":{}}`), nil }}}); err != nil {
+		t.Fatalf("WriteClientFiles failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected the file to be written: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("expected mode 0600, got %o", mode)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"signer":{}}` {
+		t.Errorf("unexpected content %q", data)
+	}
+}
+
+func TestWriteClientFilesNeedsTmpfs(t *testing.T) {
+	onTmpfs = func(string) bool { return false }
+	t.Cleanup(func() { onTmpfs = isTmpfs })
+	path := filepath.Join(t.TempDir(), "validator.json")
+
+	err := WriteClientFiles([]types.ClientFile{{Path: path, Data: func() ([]byte, error) { return []byte("secret"), nil }}})
+	if err == nil {
+		t.Fatal("expected writing a secret to disk to fail")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected nothing to be written, got %v", err)
+	}
+}
+
+func TestAwaitClientFilesRemovesReadFiles(t *testing.T) {
+	useTmpfs(t)
+	path := filepath.Join(t.TempDir(), "validator.json")
+	files := []types.ClientFile{{Path: path, Data: func() ([]byte, error) { return []byte("secret"), nil }}}
+	if err := WriteClientFiles(files); err != nil {
+		t.Fatalf("WriteClientFiles failed: %v", err)
+	}
+	if fileRead(path) {
+		t.Fatal("expected a fresh file to be unread")
+	}
+
+	cmd := exec.Command("sh", "-c", "cat \"$0\" >/dev/null; sleep 5", path)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start reader: %v", err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	start := time.Now()
+	AwaitClientFiles(files, cmd.Process.Pid)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the file to be removed, got %v", err)
+	}
+	if time.Since(start) > 4*time.Second {
+		t.Errorf("expected the file to be removed once read, took %s", time.Since(start))
+	}
+}
+
+func TestAwaitClientFilesRemovesFilesOfExitedClient(t *testing.T) {
+	useTmpfs(t)
+	path := filepath.Join(t.TempDir(), "validator.json")
+	files := []types.ClientFile{{Path: path, Data: func() ([]byte, error) { return []byte("secret"), nil }}}
+	if err := WriteClientFiles(files); err != nil {
+		t.Fatalf("WriteClientFiles failed: %v", err)
+	}
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run client: %v", err)
+	}
+	AwaitClientFiles(files, cmd.Process.Pid)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the file to be removed, got %v", err)
+	}
+}
+
+// useTmpfs treats temporary directories as a tmpfs
+func useTmpfs(t *testing.T) {
+	onTmpfs = func(string) bool { return true }
+	t.Cleanup(func() { onTmpfs = isTmpfs })
+}
@@ -0,0 +1,50 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// maxExitHistory is the number of exits kept per client
+const maxExitHistory = 20
+
+// LoadHistory returns the supervisor history for a client, or nil if it was never supervised
+func LoadHistory(name string) (*t.ClientHistory, error) {
+	data, err := os.ReadFile(historyPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var history t.ClientHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("corrupt history file for %s: %w", name, err)
+	}
+	return &history, nil
+}
+
+// SaveHistory writes the supervisor history for a client, trimming old exits
+func SaveHistory(history t.ClientHistory) error {
+	if err := os.MkdirAll(filepath.Dir(historyPath(history.Name)), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if len(history.Exits) > maxExitHistory {
+		history.Exits = history.Exits[len(history.Exits)-maxExitHistory:]
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(historyPath(history.Name), data, 0o600)
+}
+
+func historyPath(name string) string {
+	return filepath.Join(constants.StateDir, "history", name+".json")
+}
@@ -0,0 +1,245 @@
+package process
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"golang.org/x/sys/unix"
+)
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted
+const cgroupRoot = "/sys/fs/cgroup"
+
+// IO scheduling classes of ioprio_set(2)
+var ioClasses = map[string]int{
+	"realtime":    1,
+	"best-effort": 2,
+	"idle":        3,
+}
+
+const (
+	ioprioClassShift = 13
+	ioprioWhoPgrp    = 2
+)
+
+// clientCgroup is a cgroup prepared for a client, open so the client can be
+// started directly inside it
+type clientCgroup struct {
+	path string
+	dir  *os.File
+}
+
+func (c *clientCgroup) Close() {
+	if c != nil {
+		c.dir.Close()
+	}
+}
+
+// ValidateResources checks a client's resource settings before it is started
+func ValidateResources(res t.ResourcesConfig) error {
+	if res.Cgroup == "" && (res.MemoryMaxMB > 0 || res.CPUWeight > 0) {
+		return fmt.Errorf("memory_max_mb and cpu_weight need a delegated cgroup, set resources.cgroup")
+	}
+	if res.MemoryMaxMB < 0 {
+		return fmt.Errorf("memory_max_mb must not be negative")
+	}
+	if res.CPUWeight != 0 && (res.CPUWeight < 1 || res.CPUWeight > 10000) {
+		return fmt.Errorf("cpu_weight must be between 1 and 10000")
+	}
+	if res.Nice < -20 || res.Nice > 19 {
+		return fmt.Errorf("nice must be between -20 and 19")
+	}
+	if res.IOClass != "" {
+		if _, ok := ioClasses[res.IOClass]; !ok {
+			return fmt.Errorf("unknown io_class %q, use realtime, best-effort or idle", res.IOClass)
+		}
+	}
+	if res.IOPriority < 0 || res.IOPriority > 7 {
+		return fmt.Errorf("io_priority must be between 0 and 7")
+	}
+	return nil
+}
+
+// prepareCgroup creates the cgroup of a client below its configured parent,
+// applies the memory and CPU limits and attaches it to the command
+func prepareCgroup(name string, res t.ResourcesConfig, attr *syscall.SysProcAttr) (*clientCgroup, error) {
+	if res.Cgroup == "" {
+		return nil, nil
+	}
+	parent := res.Cgroup
+	if !filepath.IsAbs(parent) {
+		parent = filepath.Join(cgroupRoot, parent)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("%s is not a cgroup v2 directory: %w", parent, err)
+	}
+
+	var controllers []string
+	if res.MemoryMaxMB > 0 {
+		controllers = append(controllers, "+memory")
+	}
+	if res.CPUWeight > 0 {
+		controllers = append(controllers, "+cpu")
+	}
+	if len(controllers) > 0 {
+		control := filepath.Join(parent, "cgroup.subtree_control")
+		if err := os.WriteFile(control, []byte(strings.Join(controllers, " ")), 0644); err != nil {
+			return nil, fmt.Errorf("failed to enable %s in %s, is the cgroup delegated to this user? %w", strings.Join(controllers, " "), parent, err)
+		}
+	}
+
+	path := filepath.Join(parent, name)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup %s: %w", path, err)
+	}
+	// Reset limits a previous configuration may have left behind
+	memoryMax := "max"
+	if res.MemoryMaxMB > 0 {
+		memoryMax = strconv.FormatInt(int64(res.MemoryMaxMB)*1024*1024, 10)
+	}
+	if err := writeCgroupFile(path, "memory.max", memoryMax, res.MemoryMaxMB > 0); err != nil {
+		return nil, err
+	}
+	weight := 100
+	if res.CPUWeight > 0 {
+		weight = res.CPUWeight
+	}
+	if err := writeCgroupFile(path, "cpu.weight", strconv.Itoa(weight), res.CPUWeight > 0); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cgroup %s: %w", path, err)
+	}
+	attr.UseCgroupFD = true
+	attr.CgroupFD = int(dir.Fd())
+	return &clientCgroup{path: path, dir: dir}, nil
+}
+
+// writeCgroupFile writes a cgroup interface file. Files of controllers that
+// are not enabled don't exist, which is only an error if the limit is wanted.
+func writeCgroupFile(cgroup, file, value string, required bool) error {
+	err := os.WriteFile(filepath.Join(cgroup, file), []byte(value), 0644)
+	if err != nil && (required || !os.IsNotExist(err)) {
+		return fmt.Errorf("failed to set %s of %s: %w", file, cgroup, err)
+	}
+	return nil
+}
+
+// applyLimits sets the scheduling priorities and open file limit of a freshly
+// started client. The client leads its own session, so its process group
+// covers the children it starts later.
+func applyLimits(pid int, res t.ResourcesConfig) error {
+	if res.Nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PGRP, pid, res.Nice); err != nil {
+			return fmt.Errorf("failed to set nice %d: %w", res.Nice, err)
+		}
+	}
+	if res.IOClass != "" {
+		prio := ioClasses[res.IOClass]<<ioprioClassShift | res.IOPriority
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoPgrp, uintptr(pid), uintptr(prio)); errno != 0 {
+			return fmt.Errorf("failed to set io_class %s: %w", res.IOClass, errno)
+		}
+	}
+	if res.OpenFiles > 0 {
+		limit := unix.Rlimit{Cur: res.OpenFiles, Max: res.OpenFiles}
+		if err := unix.Prlimit(pid, unix.RLIMIT_NOFILE, &limit, nil); err != nil {
+			return fmt.Errorf("failed to set open_files %d: %w", res.OpenFiles, err)
+		}
+	}
+	return nil
+}
+
+// fillLimits adds the limits applied to a client and its cgroup's usage
+func fillLimits(info *t.ProcessInfo) {
+	if statFields, err := readStatFields(info.PID); err == nil {
+		// nice is field 19, the 17th after the command name
+		info.Nice, _ = strconv.Atoi(statFields[16])
+	}
+	info.IOClass = ioClass(info.PID)
+	info.FDLimit = openFileLimit(info.PID)
+
+	info.Cgroup = processCgroup(info.PID)
+	if info.Cgroup == "" {
+		return
+	}
+	dir := filepath.Join(cgroupRoot, info.Cgroup)
+	info.CgroupMemory, _ = readCgroupUint(dir, "memory.current")
+	info.MemLimit, _ = readCgroupUint(dir, "memory.max")
+	if weight, err := readCgroupUint(dir, "cpu.weight"); err == nil {
+		info.CPUWeight = int(weight)
+	}
+}
+
+// ioClass returns the IO scheduling class of a process, empty if it has none set
+func ioClass(pid int) string {
+	prio, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, 1, uintptr(pid), 0)
+	if errno != 0 {
+		return ""
+	}
+	class := int(prio) >> ioprioClassShift
+	for name, value := range ioClasses {
+		if value == class {
+			return fmt.Sprintf("%s (%d)", name, int(prio)&(1<<ioprioClassShift-1))
+		}
+	}
+	return ""
+}
+
+// openFileLimit returns the soft open file limit from /proc/<pid>/limits
+func openFileLimit(pid int) uint64 {
+	file, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "limits"))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(scanner.Text(), "Max open files")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return 0
+		}
+		limit, _ := strconv.ParseUint(fields[0], 10, 64)
+		return limit
+	}
+	return 0
+}
+
+// processCgroup returns the cgroup v2 path of a process relative to the root
+func processCgroup(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(string(data)), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok && path != "/" {
+			return path
+		}
+	}
+	return ""
+}
+
+// readCgroupUint reads a numeric cgroup file, "max" reads as 0
+func readCgroupUint(dir, file string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
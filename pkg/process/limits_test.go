@@ -0,0 +1,60 @@
+package process
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestLaunchClientAppliesLimits(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	spec := types.ClientSpec{
+		Name:      "limited",
+		Command:   "/bin/sh",
+		Args:      []string{"-c", "exec sleep 30"},
+		LogDir:    t.TempDir(),
+		Resources: types.ResourcesConfig{Nice: 5, IOClass: "idle", OpenFiles: 512},
+	}
+
+	client, err := LaunchClient(spec)
+	if err != nil {
+		t.Fatalf("LaunchClient failed: %v", err)
+	}
+	defer func() {
+		StopClient(client.Cmd.Process.Pid, time.Second)
+		client.Wait()
+	}()
+
+	info := &types.ProcessInfo{PID: client.Cmd.Process.Pid}
+	fillLimits(info)
+	if info.Nice != 5 {
+		t.Errorf("expected nice 5, got %d", info.Nice)
+	}
+	if info.FDLimit != 512 {
+		t.Errorf("expected an open file limit of 512, got %d", info.FDLimit)
+	}
+	if !strings.HasPrefix(info.IOClass, "idle") {
+		t.Errorf("expected the idle IO class, got %q", info.IOClass)
+	}
+}
+
+func TestValidateResources(t *testing.T) {
+	invalid := map[string]types.ResourcesConfig{
+		"memory without cgroup": {MemoryMaxMB: 1024},
+		"cpu weight range":      {Cgroup: "starknode", CPUWeight: 20000},
+		"nice range":            {Nice: 30},
+		"unknown io class":      {IOClass: "fast"},
+		"io priority range":     {IOClass: "best-effort", IOPriority: 9},
+	}
+	for name, res := range invalid {
+		if err := ValidateResources(res); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if err := ValidateResources(types.ResourcesConfig{Cgroup: "starknode", MemoryMaxMB: 1024, Nice: 10}); err != nil {
+		t.Errorf("expected valid resources, got %v", err)
+	}
+}
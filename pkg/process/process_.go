@@ -1,14 +1,17 @@
 package process
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/thebuidl-grid/starknode-kit/pkg/logrotate"
 	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
 )
 
@@ -34,83 +37,205 @@ func getSystemBootTime() (time.Time, error) {
 	return time.Time{}, fmt.Errorf("btime not found in /proc/stat")
 }
 
-func stopProcess(pid int) error {
-	p, err := os.FindProcess(pid)
+const (
+	// defaultStopTimeout is used when the caller does not specify a stop timeout
+	defaultStopTimeout = 30 * time.Second
+	// killGracePeriod is how long we wait for the kernel to reap a process after SIGKILL
+	killGracePeriod = 10 * time.Second
+)
+
+func stopProcess(pid int, timeout time.Duration) (t.StopResult, error) {
+	result := t.StopResult{PID: pid, Method: "exited"}
+	start := time.Now()
+	if timeout <= 0 {
+		timeout = defaultStopTimeout
+	}
+
+	ticks, err := getProcessStartTicks(pid)
 	if err != nil {
-		return err
+		return result, nil
 	}
-	err = p.Signal(syscall.SIGTERM)
-	if err == nil {
-		return nil
+
+	if err := signalClient(pid, syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return result, nil
+		}
+		return result, err
+	}
+	result.Method = "terminated"
+	if waitForExit(pid, ticks, timeout) {
+		result.Duration = time.Since(start)
+		return result, nil
 	}
-	time.Sleep(time.Second * 3)
-	if running := IsProcessRunning(pid); running {
-		err = p.Signal(syscall.SIGTERM)
-		if err != nil {
-			return err
+
+	if err := signalClient(pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return result, err
+	}
+	result.Method = "killed"
+	if !waitForExit(pid, ticks, killGracePeriod) {
+		return result, fmt.Errorf("process %d still running after SIGKILL", pid)
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// signalClient signals the client's whole process group when it leads one,
+// so helper processes (e.g. the beacon node started by prysm.sh) stop too
+func signalClient(pid int, sig syscall.Signal) error {
+	if pgid, err := syscall.Getpgid(pid); err == nil && pgid == pid {
+		return syscall.Kill(-pid, sig)
+	}
+	return syscall.Kill(pid, sig)
+}
+
+// waitForExit polls until the process identified by pid and start ticks is
+// gone (or a zombie) or the timeout expires. It reports whether it exited.
+func waitForExit(pid int, ticks uint64, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if !isSameProcessAlive(pid, ticks) {
+			return true
 		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// isSameProcessAlive reports whether pid still refers to the process that
+// started at ticks and has not yet exited
+func isSameProcessAlive(pid int, ticks uint64) bool {
+	current, err := getProcessStartTicks(pid)
+	if err != nil || current != ticks {
+		return false
+	}
+	return getProcessState(pid) != "Z"
+}
+
+// getProcessState returns the state letter from /proc/<pid>/stat (R, S, Z, ...)
+func getProcessState(pid int) string {
+	statFields, err := readStatFields(pid)
+	if err != nil {
+		return ""
+	}
+	return statFields[0]
+}
+
+// getProcessStartTicks returns the starttime field (clock ticks since boot) of /proc/<pid>/stat
+func getProcessStartTicks(pid int) (uint64, error) {
+	statFields, err := readStatFields(pid)
+	if err != nil {
+		return 0, err
+	}
+	// starttime is field 22, which is the 20th field after the command name
+	return strconv.ParseUint(statFields[19], 10, 64)
+}
+
+// readStatFields returns the fields of /proc/<pid>/stat that follow the command name
+func readStatFields(pid int) ([]string, error) {
+	statBytes, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	// The command name is wrapped in parentheses and may contain spaces,
+	// so only split the fields that follow it
+	stat := string(statBytes)
+	end := strings.LastIndex(stat, ")")
+	if end < 0 {
+		return nil, fmt.Errorf("malformed stat file for pid %d", pid)
+	}
+	statFields := strings.Fields(stat[end+1:])
+	if len(statFields) < 20 {
+		return nil, fmt.Errorf("malformed stat file for pid %d", pid)
+	}
+	return statFields, nil
+}
+
+// ticksToTime converts process start ticks to wall clock time
+func ticksToTime(ticks uint64) (time.Time, error) {
+	bootTime, err := getSystemBootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	startTimeSeconds := float64(ticks) / clockTicksPerSecond
+	return bootTime.Add(time.Duration(startTimeSeconds * float64(time.Second))), nil
 }
 
 func getProcessInfo(processName string) *t.ProcessInfo {
-	// Read /proc to find the process
-	procDirs, err := filepath.Glob("/proc/[0-9]*")
+	record := lookupRecord(processName)
+	if record == nil {
+		return nil
+	}
+
+	processStartTime, err := ticksToTime(record.StartTicks)
 	if err != nil {
 		return nil
 	}
 
-	for _, procDir := range procDirs {
-		// Read cmdline to get the command
-		cmdlineFile := filepath.Join(procDir, "cmdline")
-		cmdlineBytes, err := os.ReadFile(cmdlineFile)
-		if err != nil {
-			continue
-		}
+	info := &t.ProcessInfo{
+		PID:    record.PID,
+		Name:   processName,
+		Status: "running",
+		Uptime: time.Since(processStartTime),
+	}
+	return info
+}
 
-		cmdline := string(cmdlineBytes)
-		if strings.Contains(cmdline, processName) {
-			// Extract PID from directory name
-			pidStr := filepath.Base(procDir)
-			pid, err := strconv.Atoi(pidStr)
-			if err != nil {
-				continue
-			}
+// logWriterCmd builds the detached log writer process for a client
+func logWriterCmd(spec t.ClientSpec) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	opts := logrotate.OptionsFromConfig(spec.Logs)
+	cmd := exec.Command(self, LogWriterCommand,
+		"--dir", spec.LogDir,
+		"--name", spec.Name,
+		"--max-size", strconv.FormatInt(opts.MaxSize, 10),
+		"--max-age", opts.MaxAge.String(),
+		"--max-backups", strconv.Itoa(opts.MaxBackups),
+		"--compress="+strconv.FormatBool(opts.Compress),
+	)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid: true,
+	}
+	return cmd, nil
+}
 
-			// Get process status
-			statusFile := filepath.Join(procDir, "stat")
-			statusBytes, err := os.ReadFile(statusFile)
-			if err != nil {
-				continue
-			}
+// tailFile reads the last n non-empty lines of a file without loading all of it
+func tailFile(path string, n int) ([]string, error) {
+	const maxTailBytes = 64 * 1024
 
-			// Parse stat file for uptime
-			statFields := strings.Fields(string(statusBytes))
-			if len(statFields) > 21 {
-				startTimeJiffies, err := strconv.ParseUint(statFields[21], 10, 64)
-				if err == nil {
-					bootTime, err := getSystemBootTime()
-					if err != nil {
-						return nil
-					}
-
-					startTimeSeconds := float64(startTimeJiffies) / 100.0
-
-					processStartTime := bootTime.Add(time.Duration(startTimeSeconds) * time.Second)
-
-					// Calculate uptime
-					uptime := time.Since(processStartTime)
-
-					return &t.ProcessInfo{
-						PID:    pid,
-						Name:   processName,
-						Status: "running",
-						Uptime: uptime,
-					}
-				}
-			}
-		}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
+	defer f.Close()
 
-	return nil
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-maxTailBytes, 0)
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for line := range strings.SplitSeq(string(buf), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	// The first line may have been cut in half by the offset
+	if offset > 0 && len(lines) > 0 {
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
 }
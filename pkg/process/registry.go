@@ -0,0 +1,118 @@
+package process
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// SaveRecord writes the registry entry for a launched client
+func SaveRecord(record t.ProcessRecord) error {
+	if err := os.MkdirAll(constants.StateDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := recordPath(record.Name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, recordPath(record.Name))
+}
+
+// LoadRecord returns the registry entry for a client, or nil if none exists
+func LoadRecord(name string) (*t.ProcessRecord, error) {
+	data, err := os.ReadFile(recordPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record t.ProcessRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("corrupt state file for %s: %w", name, err)
+	}
+	return &record, nil
+}
+
+// RemoveRecord deletes the registry entry for a client
+func RemoveRecord(name string) error {
+	err := os.Remove(recordPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ListRecords returns every live registry entry, pruning stale ones
+func ListRecords() ([]t.ProcessRecord, error) {
+	files, err := filepath.Glob(filepath.Join(constants.StateDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var records []t.ProcessRecord
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		record := lookupRecord(name)
+		if record != nil {
+			records = append(records, *record)
+		}
+	}
+	return records, nil
+}
+
+// IsRecordAlive reports whether the process described by the record is still
+// the one we launched. A missing PID or a PID whose start time differs from
+// the recorded one (i.e. it was reused) means the record is stale.
+func IsRecordAlive(record t.ProcessRecord) bool {
+	ticks, err := getProcessStartTicks(record.PID)
+	if err != nil {
+		return false
+	}
+	return ticks == record.StartTicks
+}
+
+// IsRecordRunning is like IsRecordAlive but also treats a client that exited
+// and was not reaped yet, e.g. a zombie child of this process, as gone
+func IsRecordRunning(record t.ProcessRecord) bool {
+	return isSameProcessAlive(record.PID, record.StartTicks)
+}
+
+// ConfigHash returns a stable hash of the binary and arguments a client was launched with
+func ConfigHash(binary string, args []string) string {
+	h := sha256.New()
+	h.Write([]byte(binary))
+	for _, arg := range args {
+		h.Write([]byte{0})
+		h.Write([]byte(arg))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lookupRecord loads a record and removes it if the process is gone
+func lookupRecord(name string) *t.ProcessRecord {
+	record, err := LoadRecord(name)
+	if err != nil || record == nil {
+		return nil
+	}
+	if !IsRecordAlive(*record) {
+		RemoveRecord(name)
+		return nil
+	}
+	return record
+}
+
+func recordPath(name string) string {
+	return filepath.Join(constants.StateDir, name+".json")
+}
@@ -0,0 +1,88 @@
+package process
+
+import (
+	"os"
+	"testing"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestRegistryRoundTrip(t *testing.T) {
+	constants.StateDir = t.TempDir()
+
+	ticks, err := getProcessStartTicks(os.Getpid())
+	if err != nil {
+		t.Fatalf("failed to read own start ticks: %v", err)
+	}
+
+	record := types.ProcessRecord{
+		Name:       "geth",
+		PID:        os.Getpid(),
+		StartTicks: ticks,
+		Binary:     "/usr/bin/geth",
+		Args:       []string{"--mainnet"},
+		ConfigHash: ConfigHash("/usr/bin/geth", []string{"--mainnet"}),
+	}
+	if err := SaveRecord(record); err != nil {
+		t.Fatalf("SaveRecord failed: %v", err)
+	}
+
+	info := GetProcessInfo("geth")
+	if info == nil {
+		t.Fatal("expected geth to be reported as running")
+	}
+	if info.PID != os.Getpid() {
+		t.Errorf("expected PID %d, got %d", os.Getpid(), info.PID)
+	}
+
+	records, err := ListRecords()
+	if err != nil {
+		t.Fatalf("ListRecords failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Name != "geth" {
+		t.Errorf("expected a single geth record, got %+v", records)
+	}
+}
+
+func TestRegistryPrunesStaleRecords(t *testing.T) {
+	constants.StateDir = t.TempDir()
+
+	ticks, err := getProcessStartTicks(os.Getpid())
+	if err != nil {
+		t.Fatalf("failed to read own start ticks: %v", err)
+	}
+
+	// Same PID but a different start time, as if the PID had been reused
+	record := types.ProcessRecord{
+		Name:       "juno",
+		PID:        os.Getpid(),
+		StartTicks: ticks + 1,
+	}
+	if err := SaveRecord(record); err != nil {
+		t.Fatalf("SaveRecord failed: %v", err)
+	}
+
+	if info := GetProcessInfo("juno"); info != nil {
+		t.Errorf("expected stale record to be ignored, got PID %d", info.PID)
+	}
+
+	loaded, err := LoadRecord("juno")
+	if err != nil {
+		t.Fatalf("LoadRecord failed: %v", err)
+	}
+	if loaded != nil {
+		t.Error("expected stale record to be removed")
+	}
+}
+
+func TestConfigHashChangesWithArgs(t *testing.T) {
+	a := ConfigHash("/bin/geth", []string{"--port=30303"})
+	b := ConfigHash("/bin/geth", []string{"--port=30304"})
+	if a == b {
+		t.Error("expected different hashes for different arguments")
+	}
+	if a != ConfigHash("/bin/geth", []string{"--port=30303"}) {
+		t.Error("expected hash to be stable")
+	}
+}
@@ -0,0 +1,152 @@
+package process
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// clockTicksPerSecond is USER_HZ, the unit of the CPU times in /proc/<pid>/stat
+const clockTicksPerSecond = 100
+
+// ProcessTree returns pid followed by all of its descendants, e.g. the
+// beacon-chain binary prysm.sh starts
+func ProcessTree(pid int) []int {
+	children := make(map[int][]int)
+	entries, _ := os.ReadDir("/proc")
+	for _, entry := range entries {
+		child, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		statFields, err := readStatFields(child)
+		if err != nil {
+			continue
+		}
+		parent, err := strconv.Atoi(statFields[1])
+		if err != nil {
+			continue
+		}
+		children[parent] = append(children[parent], child)
+	}
+
+	tree := []int{pid}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, children[tree[i]]...)
+	}
+	return tree
+}
+
+// MeasureCPU samples the CPU time of a process tree over interval and returns
+// the usage in percent of one core
+func MeasureCPU(pid int, interval time.Duration) (float64, error) {
+	before, err := treeCPUTicks(ProcessTree(pid))
+	if err != nil {
+		return 0, err
+	}
+	time.Sleep(interval)
+	after, err := treeCPUTicks(ProcessTree(pid))
+	if err != nil {
+		return 0, err
+	}
+	if after < before {
+		// A child exited between the samples and took its CPU time with it
+		return 0, nil
+	}
+	seconds := float64(after-before) / clockTicksPerSecond
+	return seconds / interval.Seconds() * 100, nil
+}
+
+// fillResourceUsage adds memory, disk IO, open file and CPU figures for the
+// process tree of info.PID. CPU usage is averaged over the client's uptime;
+// use MeasureCPU for the current value.
+func fillResourceUsage(info *t.ProcessInfo) {
+	tree := ProcessTree(info.PID)
+	info.Processes = len(tree)
+
+	for _, pid := range tree {
+		info.MemUsage += residentBytes(pid)
+		read, written := ioBytes(pid)
+		info.DiskRead += read
+		info.DiskWrite += written
+		info.OpenFDs += openFDs(pid)
+	}
+
+	if ticks, err := treeCPUTicks(tree); err == nil && info.Uptime > 0 {
+		info.CPUUsage = float64(ticks) / clockTicksPerSecond / info.Uptime.Seconds() * 100
+	}
+}
+
+// treeCPUTicks sums user and system time of every process in the tree
+func treeCPUTicks(tree []int) (uint64, error) {
+	var total uint64
+	for i, pid := range tree {
+		statFields, err := readStatFields(pid)
+		if err != nil {
+			if i == 0 {
+				return 0, err
+			}
+			// Children may exit while we walk the tree
+			continue
+		}
+		// utime and stime are fields 14 and 15, the 12th and 13th after the command name
+		utime, _ := strconv.ParseUint(statFields[11], 10, 64)
+		stime, _ := strconv.ParseUint(statFields[12], 10, 64)
+		total += utime + stime
+	}
+	return total, nil
+}
+
+// residentBytes returns the resident set size of a process from /proc/<pid>/statm
+func residentBytes(pid int) uint64 {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "statm"))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, _ := strconv.ParseUint(fields[1], 10, 64)
+	return pages * uint64(os.Getpagesize())
+}
+
+// ioBytes returns the bytes a process read from and wrote to storage. The io
+// file is only readable by the process owner, so this is zero for other users.
+func ioBytes(pid int) (read, written uint64) {
+	file, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "io"))
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		switch key {
+		case "read_bytes":
+			read = n
+		case "write_bytes":
+			written = n
+		}
+	}
+	return read, written
+}
+
+// openFDs counts the entries of /proc/<pid>/fd
+func openFDs(pid int) int {
+	entries, err := os.ReadDir(filepath.Join("/proc", strconv.Itoa(pid), "fd"))
+	if err != nil {
+		return 0
+	}
+	return len(entries)
+}
@@ -0,0 +1,63 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestProcessTreeIncludesChildren(t *testing.T) {
+	cmd := exec.Command("/bin/sh", "-c", "sleep 30 & wait")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start test process: %v", err)
+	}
+	defer func() {
+		StopClient(cmd.Process.Pid, time.Second)
+		cmd.Wait()
+	}()
+
+	// Wait for the shell to fork its child
+	deadline := time.Now().Add(2 * time.Second)
+	var tree []int
+	for time.Now().Before(deadline) {
+		tree = ProcessTree(os.Getpid())
+		if len(tree) >= 3 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !slices.Contains(tree, cmd.Process.Pid) {
+		t.Fatalf("expected tree %v to contain child %d", tree, cmd.Process.Pid)
+	}
+	if len(tree) < 3 {
+		t.Errorf("expected tree to contain the grandchild sleep process, got %v", tree)
+	}
+}
+
+func TestResourceUsage(t *testing.T) {
+	info := &types.ProcessInfo{PID: os.Getpid(), Uptime: time.Minute}
+	fillResourceUsage(info)
+
+	if info.MemUsage == 0 {
+		t.Error("expected non-zero memory usage")
+	}
+	if info.OpenFDs == 0 {
+		t.Error("expected open file descriptors")
+	}
+	if info.Processes < 1 {
+		t.Errorf("expected at least one process, got %d", info.Processes)
+	}
+
+	if _, err := MeasureCPU(os.Getpid(), 50*time.Millisecond); err != nil {
+		t.Errorf("MeasureCPU failed: %v", err)
+	}
+	if _, err := MeasureCPU(-1, time.Millisecond); err == nil {
+		t.Error("expected MeasureCPU to fail for a missing process")
+	}
+}
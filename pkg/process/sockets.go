@@ -0,0 +1,149 @@
+package process
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpListen is the TCP_LISTEN state in /proc/net/tcp
+const tcpListen = "0A"
+
+// Socket is a local TCP listener or bound UDP socket
+type Socket struct {
+	Protocol string // tcp or udp
+	Address  string
+	Port     int
+	Inode    uint64
+	PID      int    // 0 if the owner could not be determined, e.g. another user's process
+	Command  string // name of the owning process
+}
+
+func (s Socket) Owner() string {
+	if s.PID == 0 {
+		return "an unknown process"
+	}
+	return fmt.Sprintf("PID %d (%s)", s.PID, s.Command)
+}
+
+// ListeningSockets returns the TCP listeners and bound UDP sockets of the host
+// from /proc/net, together with the process that owns each of them
+func ListeningSockets() ([]Socket, error) {
+	var sockets []Socket
+	for _, protocol := range []string{"tcp", "udp"} {
+		for _, name := range []string{protocol, protocol + "6"} {
+			found, err := readSocketTable(filepath.Join("/proc/net", name), protocol)
+			if os.IsNotExist(err) {
+				// IPv6 may be disabled
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			sockets = append(sockets, found...)
+		}
+	}
+
+	owners := socketOwners()
+	for i := range sockets {
+		if pid, ok := owners[sockets[i].Inode]; ok {
+			sockets[i].PID = pid
+			sockets[i].Command = processName(pid)
+		}
+	}
+	return sockets, nil
+}
+
+// readSocketTable parses one of /proc/net/{tcp,tcp6,udp,udp6}
+func readSocketTable(path, protocol string) ([]Socket, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var sockets []Socket
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		if protocol == "tcp" && fields[3] != tcpListen {
+			continue
+		}
+		address, port, err := parseSocketAddress(fields[1])
+		if err != nil {
+			continue
+		}
+		inode, _ := strconv.ParseUint(fields[9], 10, 64)
+		sockets = append(sockets, Socket{Protocol: protocol, Address: address, Port: port, Inode: inode})
+	}
+	return sockets, scanner.Err()
+}
+
+// parseSocketAddress decodes an address such as 0100007F:1F90. The IP is
+// stored as 32-bit words in host byte order.
+func parseSocketAddress(value string) (string, int, error) {
+	hexIP, hexPort, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid socket address %q", value)
+	}
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", 0, err
+	}
+	ip, err := hex.DecodeString(hexIP)
+	if err != nil || (len(ip) != net.IPv4len && len(ip) != net.IPv6len) {
+		return "", 0, fmt.Errorf("invalid socket address %q", value)
+	}
+	for word := 0; word < len(ip); word += 4 {
+		ip[word], ip[word+1], ip[word+2], ip[word+3] = ip[word+3], ip[word+2], ip[word+1], ip[word]
+	}
+	return net.IP(ip).String(), int(port), nil
+}
+
+// socketOwners maps socket inodes to the processes holding them. Only the
+// file descriptors of processes we may inspect are visible.
+func socketOwners() map[uint64]int {
+	owners := make(map[uint64]int)
+	entries, _ := os.ReadDir("/proc")
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(target, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]"), 10, 64)
+			if err == nil {
+				owners[inode] = pid
+			}
+		}
+	}
+	return owners
+}
+
+// processName returns the command name of a process from /proc/<pid>/comm
+func processName(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "comm"))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,52 @@
+package process
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+func TestParseSocketAddress(t *testing.T) {
+	tests := []struct {
+		value   string
+		address string
+		port    int
+	}{
+		{"0100007F:1F90", "127.0.0.1", 8080},
+		{"00000000:2161", "0.0.0.0", 8545},
+		{"00000000000000000000000000000000:76C7", "::", 30407},
+		{"00000000000000000000000001000000:0016", "::1", 22},
+	}
+	for _, tt := range tests {
+		address, port, err := parseSocketAddress(tt.value)
+		if err != nil {
+			t.Fatalf("parseSocketAddress(%q) failed: %v", tt.value, err)
+		}
+		if address != tt.address || port != tt.port {
+			t.Errorf("parseSocketAddress(%q) = %s:%d, expected %s:%d", tt.value, address, port, tt.address, tt.port)
+		}
+	}
+}
+
+func TestListeningSocketsFindsOwner(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	sockets, err := ListeningSockets()
+	if err != nil {
+		t.Fatalf("ListeningSockets failed: %v", err)
+	}
+	for _, s := range sockets {
+		if s.Protocol == "tcp" && s.Port == port {
+			if s.Address != "127.0.0.1" || s.PID != os.Getpid() {
+				t.Errorf("expected 127.0.0.1 owned by PID %d, got %+v", os.Getpid(), s)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a listener on port %d in %+v", port, sockets)
+}
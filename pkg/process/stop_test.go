@@ -0,0 +1,65 @@
+package process
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func startTestProcess(t *testing.T, script string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("/bin/sh", "-c", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start test process: %v", err)
+	}
+	// Reap the child like a supervisor would so it does not linger as a zombie
+	go cmd.Wait()
+	return cmd
+}
+
+func TestStopClientTerminates(t *testing.T) {
+	cmd := startTestProcess(t, "exec sleep 30")
+
+	result, err := StopClient(cmd.Process.Pid, 5*time.Second)
+	if err != nil {
+		t.Fatalf("StopClient failed: %v", err)
+	}
+	if result.Method != "terminated" {
+		t.Errorf("expected method terminated, got %s", result.Method)
+	}
+}
+
+func TestStopClientEscalatesToKill(t *testing.T) {
+	cmd := startTestProcess(t, `trap "" TERM; while true; do sleep 0.1; done`)
+	// Give the shell time to install its trap
+	time.Sleep(200 * time.Millisecond)
+
+	result, err := StopClient(cmd.Process.Pid, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("StopClient failed: %v", err)
+	}
+	if result.Method != "killed" {
+		t.Errorf("expected method killed, got %s", result.Method)
+	}
+	// The process may be reaped at any moment, so check its state only once
+	if state := getProcessState(cmd.Process.Pid); state != "" && state != "Z" {
+		t.Error("expected process to be gone after StopClient returned")
+	}
+}
+
+func TestStopClientNotRunning(t *testing.T) {
+	cmd := exec.Command("/bin/true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run test process: %v", err)
+	}
+
+	result, err := StopClient(cmd.Process.Pid, time.Second)
+	if err != nil {
+		t.Fatalf("StopClient failed: %v", err)
+	}
+	if result.Method != "exited" {
+		t.Errorf("expected method exited, got %s", result.Method)
+	}
+}
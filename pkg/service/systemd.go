@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+const unitPrefix = "starknode-"
+
+// UnitStatus is the systemd state of a generated unit
+type UnitStatus struct {
+	Unit        string
+	ActiveState string
+	SubState    string
+	MainPID     string
+}
+
+// UnitName returns the systemd unit name used for a client
+func UnitName(client string) string {
+	return unitPrefix + client + ".service"
+}
+
+// UnitDir returns the directory units are installed to for the given scope
+func UnitDir(userScope bool) (string, error) {
+	if !userScope {
+		return "/etc/systemd/system", nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "systemd", "user"), nil
+}
+
+// Install writes one unit per client, removes units of clients that are no
+// longer configured, reloads systemd and enables the units. Clients must be
+// given in start order; each unit is ordered after the previous one.
+func Install(clients []types.IClient, userScope bool) ([]string, error) {
+	dir, err := UnitDir(userScope)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create unit directory: %w", err)
+	}
+
+	runAs := ""
+	if !userScope {
+		if u, err := user.Current(); err == nil {
+			runAs = u.Username
+		}
+	}
+
+	var units []string
+	previous := ""
+	for _, client := range clients {
+		spec := client.Spec()
+		unit := UnitName(spec.Name)
+		content := RenderUnit(spec, previous, runAs, userScope)
+		// Units can carry secrets in ExecStart, keep them private
+		if err := os.WriteFile(filepath.Join(dir, unit), []byte(content), 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", unit, err)
+		}
+		units = append(units, unit)
+		previous = unit
+	}
+
+	installed, err := InstalledUnits(userScope)
+	if err != nil {
+		return nil, err
+	}
+	for _, unit := range installed {
+		if !slices.Contains(units, unit) {
+			systemctl(userScope, "disable", "--now", unit)
+			if err := os.Remove(filepath.Join(dir, unit)); err != nil {
+				return nil, fmt.Errorf("failed to remove stale unit %s: %w", unit, err)
+			}
+		}
+	}
+
+	if err := systemctl(userScope, "daemon-reload"); err != nil {
+		return nil, err
+	}
+	if len(units) > 0 {
+		if err := systemctl(userScope, append([]string{"enable"}, units...)...); err != nil {
+			return nil, err
+		}
+	}
+	return units, nil
+}
+
+// Uninstall stops, disables and removes every generated unit
+func Uninstall(userScope bool) ([]string, error) {
+	dir, err := UnitDir(userScope)
+	if err != nil {
+		return nil, err
+	}
+	units, err := InstalledUnits(userScope)
+	if err != nil {
+		return nil, err
+	}
+	for _, unit := range units {
+		systemctl(userScope, "disable", "--now", unit)
+		if err := os.Remove(filepath.Join(dir, unit)); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", unit, err)
+		}
+	}
+	if len(units) > 0 {
+		if err := systemctl(userScope, "daemon-reload"); err != nil {
+			return nil, err
+		}
+	}
+	return units, nil
+}
+
+// Status returns the systemd state of every generated unit
+func Status(userScope bool) ([]UnitStatus, error) {
+	units, err := InstalledUnits(userScope)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []UnitStatus
+	for _, unit := range units {
+		status := UnitStatus{Unit: unit}
+		args := []string{"show", unit, "-p", "ActiveState", "-p", "SubState", "-p", "MainPID"}
+		if userScope {
+			args = append([]string{"--user"}, args...)
+		}
+		out, err := exec.Command("systemctl", args...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("systemctl show %s failed: %w", unit, err)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			key, value, _ := strings.Cut(line, "=")
+			switch key {
+			case "ActiveState":
+				status.ActiveState = value
+			case "SubState":
+				status.SubState = value
+			case "MainPID":
+				status.MainPID = value
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// InstalledUnits lists the generated unit files present in the unit directory
+func InstalledUnits(userScope bool) ([]string, error) {
+	dir, err := UnitDir(userScope)
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, unitPrefix+"*.service"))
+	if err != nil {
+		return nil, err
+	}
+	var units []string
+	for _, file := range files {
+		units = append(units, filepath.Base(file))
+	}
+	return units, nil
+}
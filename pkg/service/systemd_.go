@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// executable is the starknode-kit binary units call back into
+var executable = os.Executable
+
+// RenderUnit renders the systemd unit for a client. after is the unit of the
+// client it depends on, empty for the first client in the stack.
+func RenderUnit(spec types.ClientSpec, after, runAs string, userScope bool) string {
+	var b strings.Builder
+
+	b.WriteString("# Generated by starknode-kit, re-run `starknode-kit service install` after changing the config\n")
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=starknode-kit %s client\n", spec.Name)
+	if userScope {
+		b.WriteString("After=network-online.target")
+	} else {
+		b.WriteString("Wants=network-online.target\n")
+		b.WriteString("After=network-online.target")
+	}
+	if after != "" {
+		fmt.Fprintf(&b, " %s\n", after)
+		fmt.Fprintf(&b, "Requires=%s\n", after)
+	} else {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	if runAs != "" {
+		fmt.Fprintf(&b, "User=%s\n", runAs)
+	}
+	for _, env := range spec.Env {
+		fmt.Fprintf(&b, "Environment=%s\n", quoteEnv(env))
+	}
+	var files map[string]string
+	if len(spec.Files) > 0 {
+		files = writeClientFiles(&b, spec)
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", execStart(spec.Command, spec.Args, files))
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=10\n")
+	if spec.StopTimeout > 0 {
+		fmt.Fprintf(&b, "TimeoutStopSec=%d\n", int(spec.StopTimeout.Seconds()))
+	}
+	writeResources(&b, spec.Resources)
+	b.WriteString("StandardOutput=journal\n")
+	b.WriteString("StandardError=journal\n")
+	fmt.Fprintf(&b, "SyslogIdentifier=%s%s\n", unitPrefix, spec.Name)
+
+	b.WriteString("\n[Install]\n")
+	if userScope {
+		b.WriteString("WantedBy=default.target\n")
+	} else {
+		b.WriteString("WantedBy=multi-user.target\n")
+	}
+	return b.String()
+}
+
+// writeClientFiles renders the directives that write the client's files to a
+// runtime directory systemd creates on every start and removes on stop, and
+// remove them once the client has read them. It returns where each file is
+// found in that directory.
+func writeClientFiles(b *strings.Builder, spec types.ClientSpec) map[string]string {
+	runtimeDir := unitPrefix + spec.Name
+	dir := "%t/" + runtimeDir
+	self, err := executable()
+	if err != nil {
+		self = "starknode-kit"
+	}
+
+	fmt.Fprintf(b, "RuntimeDirectory=%s\n", runtimeDir)
+	b.WriteString("RuntimeDirectoryMode=0700\n")
+	fmt.Fprintf(b, "ExecStartPre=%s %s write %s --dir %s\n", quoteArg(self), process.ClientFilesCommand, quoteArg(spec.Name), dir)
+	fmt.Fprintf(b, "ExecStartPost=%s %s cleanup %s --dir %s --pid $MAINPID\n", quoteArg(self), process.ClientFilesCommand, quoteArg(spec.Name), dir)
+
+	files := make(map[string]string)
+	for _, file := range spec.Files {
+		files[file.Path] = dir + "/" + quoteArg(filepath.Base(file.Path))
+	}
+	return files
+}
+
+// writeResources renders the client's resource limits as unit directives.
+// systemd places every unit in its own cgroup, so memory and CPU limits apply
+// without a configured cgroup.
+func writeResources(b *strings.Builder, res types.ResourcesConfig) {
+	if res.MemoryMaxMB > 0 {
+		fmt.Fprintf(b, "MemoryMax=%dM\n", res.MemoryMaxMB)
+	}
+	if res.CPUWeight > 0 {
+		fmt.Fprintf(b, "CPUWeight=%d\n", res.CPUWeight)
+	}
+	if res.Nice != 0 {
+		fmt.Fprintf(b, "Nice=%d\n", res.Nice)
+	}
+	if res.IOClass != "" {
+		fmt.Fprintf(b, "IOSchedulingClass=%s\n", res.IOClass)
+		fmt.Fprintf(b, "IOSchedulingPriority=%d\n", res.IOPriority)
+	}
+	if res.OpenFiles > 0 {
+		fmt.Fprintf(b, "LimitNOFILE=%d\n", res.OpenFiles)
+	}
+}
+
+// execStart joins a command line using systemd's quoting rules. Args naming a
+// client file are replaced by its path in the unit's runtime directory.
+func execStart(command string, args []string, files map[string]string) string {
+	parts := []string{quoteArg(command)}
+	for _, arg := range args {
+		if path, ok := files[arg]; ok {
+			parts = append(parts, path)
+			continue
+		}
+		parts = append(parts, quoteArg(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteArg escapes systemd specifiers and variable expansion, quoting
+// arguments that contain whitespace or quotes
+func quoteArg(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	arg = strings.ReplaceAll(arg, "$", "$$")
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\;") {
+		return arg
+	}
+	arg = strings.ReplaceAll(arg, `\`, `\\`)
+	arg = strings.ReplaceAll(arg, `"`, `\"`)
+	return `"` + arg + `"`
+}
+
+// quoteEnv quotes a KEY=VALUE pair for Environment=, which expands specifiers
+// but not variables
+func quoteEnv(env string) string {
+	env = strings.ReplaceAll(env, "%", "%%")
+	if !strings.ContainsAny(env, " \t\"'\\") {
+		return env
+	}
+	env = strings.ReplaceAll(env, `\`, `\\`)
+	env = strings.ReplaceAll(env, `"`, `\"`)
+	return `"` + env + `"`
+}
+
+func systemctl(userScope bool, args ...string) error {
+	if userScope {
+		args = append([]string{"--user"}, args...)
+	}
+	out, err := exec.Command("systemctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
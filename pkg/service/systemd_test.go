@@ -0,0 +1,105 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func TestRenderUnit(t *testing.T) {
+	spec := types.ClientSpec{
+		Name:        "lighthouse",
+		Command:     "/opt/starknode-kit/lighthouse",
+		Args:        []string{"bn", "--network", "mainnet", "--graffiti", "my node"},
+		Env:         []string{"RUST_LOG=info", "GREETING=hello world"},
+		StopTimeout: 2 * time.Minute,
+		Resources:   types.ResourcesConfig{MemoryMaxMB: 8192, Nice: 5, IOClass: "idle", OpenFiles: 65536},
+	}
+
+	unit := RenderUnit(spec, UnitName("geth"), "node", false)
+
+	expected := []string{
+		"Description=starknode-kit lighthouse client",
+		"After=network-online.target starknode-geth.service",
+		"Requires=starknode-geth.service",
+		"User=node",
+		"Environment=RUST_LOG=info",
+		`Environment="GREETING=hello world"`,
+		`ExecStart=/opt/starknode-kit/lighthouse bn --network mainnet --graffiti "my node"`,
+		"Restart=on-failure",
+		"TimeoutStopSec=120",
+		"MemoryMax=8192M",
+		"Nice=5",
+		"IOSchedulingClass=idle",
+		"LimitNOFILE=65536",
+		"StandardOutput=journal",
+		"SyslogIdentifier=starknode-lighthouse",
+		"WantedBy=multi-user.target",
+	}
+	for _, line := range expected {
+		if !strings.Contains(unit, line+"\n") {
+			t.Errorf("expected unit to contain %q, got:\n%s", line, unit)
+		}
+	}
+}
+
+func TestRenderUserUnitWithoutDependency(t *testing.T) {
+	spec := types.ClientSpec{Name: "geth", Command: "/opt/geth", Args: []string{"--mainnet"}}
+
+	unit := RenderUnit(spec, "", "", true)
+
+	if strings.Contains(unit, "Requires=") {
+		t.Error("first unit in the stack should not require another unit")
+	}
+	if strings.Contains(unit, "User=") {
+		t.Error("user units should not set User=")
+	}
+	if !strings.Contains(unit, "WantedBy=default.target\n") {
+		t.Error("user units should be wanted by default.target")
+	}
+}
+
+func TestQuoteArg(t *testing.T) {
+	cases := map[string]string{
+		"--http.corsdomain=*": "--http.corsdomain=*",
+		"100%":                "100%%",
+		"$HOME":               "$$HOME",
+		"a b":                 `"a b"`,
+		`say "hi"`:            `"say \"hi\""`,
+		"":                    `""`,
+	}
+	for in, want := range cases {
+		if got := quoteArg(in); got != want {
+			t.Errorf("quoteArg(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderUnitWithClientFiles(t *testing.T) {
+	executable = func() (string, error) { return "/usr/local/bin/starknode-kit", nil }
+	t.Cleanup(func() { executable = os.Executable })
+	spec := types.ClientSpec{
+		Name:    "starknet-staking-v2",
+		Command: "/opt/validator",
+		Args:    []string{"--config", "/run/user/1000/starknode-kit/starknet-staking-v2.json", "--signer-op-address", "0x123"},
+		Files:   []types.ClientFile{{Path: "/run/user/1000/starknode-kit/starknet-staking-v2.json"}},
+	}
+
+	unit := RenderUnit(spec, "", "node", false)
+
+	expected := []string{
+		"RuntimeDirectory=starknode-starknet-staking-v2",
+		"RuntimeDirectoryMode=0700",
+		"ExecStartPre=/usr/local/bin/starknode-kit client-files write starknet-staking-v2 --dir %t/starknode-starknet-staking-v2",
+		"ExecStartPost=/usr/local/bin/starknode-kit client-files cleanup starknet-staking-v2 --dir %t/starknode-starknet-staking-v2 --pid $MAINPID",
+		"ExecStart=/opt/validator --config %t/starknode-starknet-staking-v2/starknet-staking-v2.json --signer-op-address 0x123",
+	}
+	for _, line := range expected {
+		if !strings.Contains(unit, line+"\n") {
+			t.Errorf("expected unit to contain %q, got:\n%s", line, unit)
+		}
+	}
+}
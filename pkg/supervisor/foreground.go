@@ -0,0 +1,105 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// RunForeground starts the stack in dependency order and keeps the clients
+// attached, writing their output to out with each line prefixed by the client
+// name. When ctx is cancelled the clients are stopped in reverse dependency
+// order. If any client exits on its own the rest are stopped too and an error
+// describing the exit is returned, so the caller can exit non-zero.
+func RunForeground(ctx context.Context, cfg types.StarkNodeKitConfig, stack []clients.StackClient, out io.Writer, readyTimeout time.Duration) error {
+	for _, c := range stack {
+		if info := process.GetProcessInfo(string(c.Type)); info != nil {
+			return fmt.Errorf("client %s is already running in the background (PID %d), stop it first", c.Type, info.PID)
+		}
+	}
+
+	names := make([]string, 0, len(stack))
+	for _, c := range stack {
+		names = append(names, string(c.Type))
+	}
+	mux := newOutputMux(out, names)
+
+	// runCtx is cancelled by a shutdown request or the first client to exit
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		running []*attachedClient
+		failure error
+	)
+	ready := make(map[types.ClientType]bool)
+	for _, c := range stack {
+		if err := waitForDependencies(runCtx, cfg, c, ready, readyTimeout); err != nil {
+			if runCtx.Err() == nil {
+				failure = err
+			}
+			break
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+
+		a, err := attach(c.Client.Spec(), mux.writer(string(c.Type)), cancel)
+		if err != nil {
+			failure = fmt.Errorf("error starting %s: %w", c.Type, err)
+			break
+		}
+		log.Printf(utils.Green("Started %s (PID %d)"), c.Type, a.pid())
+		running = append(running, a)
+	}
+
+	if failure == nil {
+		<-runCtx.Done()
+	}
+
+	// Dependents go first, then the clients they depend on
+	for _, a := range slices.Backward(running) {
+		a.stop()
+	}
+	if failure == nil {
+		// Report the client that brought the stack down
+		var first *attachedClient
+		for _, a := range running {
+			if !a.stopped && (first == nil || a.exit.ExitedAt.Before(first.exit.ExitedAt)) {
+				first = a
+			}
+		}
+		if first != nil {
+			failure = errors.New(describeExit(first.spec.Name, first.exit))
+		}
+	}
+	mux.flush()
+	return failure
+}
+
+func waitForDependencies(ctx context.Context, cfg types.StarkNodeKitConfig, c clients.StackClient, ready map[types.ClientType]bool, readyTimeout time.Duration) error {
+	for _, dep := range c.DependsOn {
+		if ready[dep] {
+			continue
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, readyTimeout)
+		err := clients.WaitReady(waitCtx, cfg, dep, func(probe clients.ReadinessProbe) {
+			log.Printf(utils.Cyan("Waiting for %s: %s"), dep, probe.Description)
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("not starting %s: %w", c.Type, err)
+		}
+		ready[dep] = true
+	}
+	return nil
+}
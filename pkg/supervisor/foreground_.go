@@ -0,0 +1,124 @@
+package supervisor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// attachedClient is a client running in the foreground
+type attachedClient struct {
+	spec    types.ClientSpec
+	client  *process.LaunchedClient
+	done    chan struct{}
+	exit    types.ExitRecord
+	stopped bool // stopped on request rather than exiting on its own
+}
+
+// attach launches a client with its output copied to out. onExit is called
+// once the client has exited.
+func attach(spec types.ClientSpec, out io.Writer, onExit func()) (*attachedClient, error) {
+	client, err := process.LaunchAttached(spec, out)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &attachedClient{spec: spec, client: client, done: make(chan struct{})}
+	go func() {
+		waitErr := client.Wait()
+		a.exit = exitRecord(client.Cmd, waitErr)
+		process.RemoveRecord(spec.Name)
+		close(a.done)
+		onExit()
+	}()
+	return a, nil
+}
+
+func (a *attachedClient) pid() int {
+	return a.client.Cmd.Process.Pid
+}
+
+// stop stops the client unless it has already exited and waits for it
+func (a *attachedClient) stop() {
+	select {
+	case <-a.done:
+		return
+	default:
+	}
+
+	log.Printf(utils.Cyan("Stopping %s (PID %d)"), a.spec.Name, a.pid())
+	result, err := process.StopClient(a.pid(), a.spec.StopTimeout)
+	if err != nil {
+		log.Printf(utils.Red("Could not stop %s: %v"), a.spec.Name, err)
+	} else if result.Method == "killed" {
+		log.Printf(utils.Yellow("%s did not exit within %s and was killed"), a.spec.Name, a.spec.StopTimeout)
+	}
+	<-a.done
+	a.stopped = true
+}
+
+// outputMux interleaves the output of several clients line by line
+type outputMux struct {
+	mu      sync.Mutex
+	out     io.Writer
+	width   int
+	writers []*prefixWriter
+}
+
+func newOutputMux(out io.Writer, names []string) *outputMux {
+	m := &outputMux{out: out}
+	for _, name := range names {
+		m.width = max(m.width, len(name))
+	}
+	return m
+}
+
+func (m *outputMux) writer(name string) io.Writer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := &prefixWriter{mux: m, prefix: fmt.Sprintf("%-*s | ", m.width, name)}
+	m.writers = append(m.writers, w)
+	return w
+}
+
+// flush writes out any trailing output that did not end with a newline
+func (m *outputMux) flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, w := range m.writers {
+		if w.buf.Len() > 0 {
+			fmt.Fprintf(m.out, "%s%s\n", w.prefix, w.buf.Bytes())
+			w.buf.Reset()
+		}
+	}
+}
+
+// prefixWriter prefixes every complete line with the client name
+type prefixWriter struct {
+	mux    *outputMux
+	prefix string
+	buf    bytes.Buffer
+}
+
+// Write never fails so a closed stdout can't take down the client's log file
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mux.mu.Lock()
+	defer w.mux.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf.Next(i + 1)
+		w.mux.out.Write(append([]byte(w.prefix), line...))
+	}
+	return len(p), nil
+}
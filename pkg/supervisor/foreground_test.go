@@ -0,0 +1,75 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/clients"
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func foregroundStack(t *testing.T, scripts map[string]string) []clients.StackClient {
+	var stack []clients.StackClient
+	for name, script := range scripts {
+		client := fakeClient{spec: types.ClientSpec{
+			Name:        name,
+			Command:     "/bin/sh",
+			Args:        []string{"-c", script},
+			LogDir:      t.TempDir(),
+			StopTimeout: 5 * time.Second,
+		}}
+		stack = append(stack, clients.StackClient{
+			StackEntry: clients.StackEntry{Type: types.ClientType(name)},
+			Client:     client,
+		})
+	}
+	return stack
+}
+
+func TestRunForegroundClientDies(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	stack := foregroundStack(t, map[string]string{
+		"sleeper": "echo up; exec sleep 30",
+		"crasher": "sleep 0.2; echo bad flag; exit 3",
+	})
+
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := RunForeground(ctx, types.StarkNodeKitConfig{}, stack, &out, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "crasher") || !strings.Contains(err.Error(), "exited with code 3") {
+		t.Fatalf("expected the crash to be reported, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected the remaining clients to be stopped before the deadline")
+	}
+
+	for _, line := range []string{"sleeper | up\n", "crasher | bad flag\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+	if records, _ := process.ListRecords(); len(records) != 0 {
+		t.Errorf("expected all records to be removed, got %+v", records)
+	}
+}
+
+func TestRunForegroundSignal(t *testing.T) {
+	constants.StateDir = t.TempDir()
+	stack := foregroundStack(t, map[string]string{"sleeper": "printf partial; exec sleep 30"})
+
+	var out bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+	if err := RunForeground(ctx, types.StarkNodeKitConfig{}, stack, &out, time.Second); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+	if out.String() != "sleeper | partial\n" {
+		t.Errorf("expected the trailing partial line to be flushed, got %q", out.String())
+	}
+}
@@ -0,0 +1,85 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// Options controls how the supervisor restarts crashed clients
+type Options struct {
+	InitialBackoff  time.Duration // delay before the first restart
+	MaxBackoff      time.Duration // upper bound for the exponential backoff
+	CrashLoopLimit  int           // exits within CrashLoopWindow before giving up
+	CrashLoopWindow time.Duration
+	LogTailLines    int // log lines captured with every exit
+}
+
+// DefaultOptions returns the restart policy used by `starknode-kit supervise`
+func DefaultOptions() Options {
+	return Options{
+		InitialBackoff:  5 * time.Second,
+		MaxBackoff:      5 * time.Minute,
+		CrashLoopLimit:  5,
+		CrashLoopWindow: 15 * time.Minute,
+		LogTailLines:    20,
+	}
+}
+
+// Supervisor owns a set of clients, reaps their exits and restarts them
+type Supervisor struct {
+	clients []types.IClient
+	opts    Options
+	wg      sync.WaitGroup
+}
+
+// New creates a supervisor for the given clients
+func New(clients []types.IClient, opts Options) *Supervisor {
+	return &Supervisor{clients: clients, opts: opts}
+}
+
+// Run launches every client and keeps them alive until ctx is cancelled,
+// then stops them and waits for all of them to exit
+func (s *Supervisor) Run(ctx context.Context) error {
+	for _, client := range s.clients {
+		name := client.Spec().Name
+		if info := process.GetProcessInfo(name); info != nil {
+			return fmt.Errorf("client %s is already running (PID %d), stop it before supervising", name, info.PID)
+		}
+	}
+
+	for _, client := range s.clients {
+		s.wg.Add(1)
+		go func(c types.IClient) {
+			defer s.wg.Done()
+			s.superviseClient(ctx, c)
+		}(client)
+	}
+
+	s.wg.Wait()
+	return nil
+}
+
+// DescribeExit renders an exit record as a short human readable string
+func DescribeExit(exit types.ExitRecord) string {
+	switch {
+	case exit.Stopped == "killed":
+		return "stopped on request, killed after the stop timeout"
+	case exit.Stopped != "":
+		return "stopped on request"
+	case exit.Error != "":
+		return fmt.Sprintf("failed: %s", exit.Error)
+	case exit.Signal != "":
+		return fmt.Sprintf("killed by signal %s", exit.Signal)
+	default:
+		return fmt.Sprintf("exited with code %d", exit.ExitCode)
+	}
+}
+
+func describeExit(name string, exit types.ExitRecord) string {
+	return fmt.Sprintf("%s (PID %d) %s", name, exit.PID, DescribeExit(exit))
+}
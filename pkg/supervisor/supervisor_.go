@@ -0,0 +1,148 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// superviseClient runs a single client until ctx is cancelled or it crash-loops
+func (s *Supervisor) superviseClient(ctx context.Context, client types.IClient) {
+	spec := client.Spec()
+	history := loadHistory(spec.Name)
+	backoff := s.opts.InitialBackoff
+	var recentExits []time.Time
+
+	for {
+		startedAt := time.Now()
+		exit := s.runOnce(ctx, spec, history)
+		exit.StartedAt = startedAt
+
+		if ctx.Err() != nil {
+			history.Exits = append(history.Exits, exit)
+			history.State = "stopped"
+			saveHistory(history)
+			return
+		}
+
+		history.Exits = append(history.Exits, exit)
+		log.Print(utils.Red(describeExit(spec.Name, exit)))
+
+		// A client that stayed up for a while is not crash-looping, reset the backoff
+		if exit.ExitedAt.Sub(startedAt) > s.opts.MaxBackoff {
+			backoff = s.opts.InitialBackoff
+		}
+
+		recentExits = append(recentExits, exit.ExitedAt)
+		cutoff := time.Now().Add(-s.opts.CrashLoopWindow)
+		for len(recentExits) > 0 && recentExits[0].Before(cutoff) {
+			recentExits = recentExits[1:]
+		}
+		if len(recentExits) >= s.opts.CrashLoopLimit {
+			history.State = "crash-loop"
+			saveHistory(history)
+			log.Printf(utils.Red("%s exited %d times in %s, giving up"), spec.Name, len(recentExits), s.opts.CrashLoopWindow)
+			return
+		}
+
+		history.State = "backoff"
+		saveHistory(history)
+		log.Printf(utils.Yellow("Restarting %s in %s"), spec.Name, backoff)
+
+		select {
+		case <-ctx.Done():
+			history.State = "stopped"
+			saveHistory(history)
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.opts.MaxBackoff)
+		history.Restarts++
+	}
+}
+
+// runOnce launches the client and blocks until it exits or ctx is cancelled
+func (s *Supervisor) runOnce(ctx context.Context, spec types.ClientSpec, history *types.ClientHistory) types.ExitRecord {
+	client, err := process.LaunchClient(spec)
+	if err != nil {
+		return types.ExitRecord{ExitedAt: time.Now(), ExitCode: -1, Error: err.Error()}
+	}
+	cmd := client.Cmd
+
+	history.State = "running"
+	saveHistory(history)
+	log.Printf(utils.Green("Started %s (PID %d)"), spec.Name, cmd.Process.Pid)
+
+	done := make(chan error, 1)
+	go func() { done <- client.Wait() }()
+
+	var (
+		waitErr error
+		stopped string
+	)
+	select {
+	case waitErr = <-done:
+	case <-ctx.Done():
+		log.Printf(utils.Cyan("Stopping %s (PID %d)"), spec.Name, cmd.Process.Pid)
+		result, err := process.StopClient(cmd.Process.Pid, spec.StopTimeout)
+		if err != nil {
+			log.Printf(utils.Red("Could not stop %s: %v"), spec.Name, err)
+		} else if result.Method == "killed" {
+			log.Printf(utils.Yellow("%s did not exit within %s and was killed"), spec.Name, spec.StopTimeout)
+		}
+		stopped = result.Method
+		waitErr = <-done
+	}
+	process.RemoveRecord(spec.Name)
+
+	exit := exitRecord(cmd, waitErr)
+	exit.Stopped = stopped
+	exit.LogTail, _ = process.TailLog(client.LogFile, s.opts.LogTailLines)
+	return exit
+}
+
+// exitRecord extracts the exit code or terminating signal of a reaped command
+func exitRecord(cmd *exec.Cmd, waitErr error) types.ExitRecord {
+	exit := types.ExitRecord{
+		PID:      cmd.Process.Pid,
+		ExitedAt: time.Now(),
+		ExitCode: -1,
+	}
+	if cmd.ProcessState == nil {
+		if waitErr != nil {
+			exit.Error = waitErr.Error()
+		}
+		return exit
+	}
+
+	exit.ExitCode = cmd.ProcessState.ExitCode()
+	if status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		exit.Signal = status.Signal().String()
+	}
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		exit.Error = waitErr.Error()
+	}
+	return exit
+}
+
+func loadHistory(name string) *types.ClientHistory {
+	history, err := process.LoadHistory(name)
+	if err != nil || history == nil {
+		return &types.ClientHistory{Name: name}
+	}
+	return history
+}
+
+func saveHistory(history *types.ClientHistory) {
+	if err := process.SaveHistory(*history); err != nil {
+		log.Printf(utils.Yellow("Could not save history for %s: %v"), history.Name, err)
+	}
+}
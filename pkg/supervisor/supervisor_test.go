@@ -0,0 +1,100 @@
+package supervisor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/process"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+type fakeClient struct {
+	spec types.ClientSpec
+}
+
+func (f fakeClient) Start() error           { return process.StartClient(f.spec) }
+func (f fakeClient) Spec() types.ClientSpec { return f.spec }
+
+func TestSupervisorGivesUpOnCrashLoop(t *testing.T) {
+	constants.StateDir = t.TempDir()
+
+	client := fakeClient{spec: types.ClientSpec{
+		Name:    "crasher",
+		Command: "/bin/sh",
+		Args:    []string{"-c", "echo booting; echo bad flag; exit 3"},
+		LogDir:  t.TempDir(),
+	}}
+	opts := Options{
+		InitialBackoff:  10 * time.Millisecond,
+		MaxBackoff:      40 * time.Millisecond,
+		CrashLoopLimit:  3,
+		CrashLoopWindow: time.Minute,
+		LogTailLines:    5,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := New([]types.IClient{client}, opts).Run(ctx); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	history, err := process.LoadHistory("crasher")
+	if err != nil || history == nil {
+		t.Fatalf("expected history to be recorded, got %v", err)
+	}
+	if history.State != "crash-loop" {
+		t.Errorf("expected state crash-loop, got %s", history.State)
+	}
+	if len(history.Exits) != 3 {
+		t.Fatalf("expected 3 exits, got %d", len(history.Exits))
+	}
+	if history.Restarts != 2 {
+		t.Errorf("expected 2 restarts, got %d", history.Restarts)
+	}
+	last := history.Exits[2]
+	if last.ExitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", last.ExitCode)
+	}
+	if len(last.LogTail) == 0 || last.LogTail[len(last.LogTail)-1] != "bad flag" {
+		t.Errorf("unexpected log tail: %v", last.LogTail)
+	}
+}
+
+func TestSupervisorStopsClientsOnCancel(t *testing.T) {
+	constants.StateDir = t.TempDir()
+
+	client := fakeClient{spec: types.ClientSpec{
+		Name:    "sleeper",
+		Command: "/bin/sleep",
+		Args:    []string{"60"},
+		LogDir:  t.TempDir(),
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- New([]types.IClient{client}, DefaultOptions()).Run(ctx) }()
+
+	time.Sleep(200 * time.Millisecond)
+	if info := process.GetProcessInfo("sleeper"); info == nil {
+		t.Fatal("expected sleeper to be registered as running")
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop the client")
+	}
+	history, _ := process.LoadHistory("sleeper")
+	if history == nil || history.State != "stopped" {
+		t.Fatalf("expected state stopped, got %+v", history)
+	}
+	if len(history.Exits) != 1 || history.Exits[0].Stopped != "terminated" {
+		t.Errorf("expected one exit stopped by SIGTERM, got %+v", history.Exits)
+	}
+	if info := process.GetProcessInfo("sleeper"); info != nil {
+		t.Error("expected registry entry to be removed")
+	}
+}
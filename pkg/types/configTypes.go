@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type ClientType string
 
 const (
@@ -32,12 +34,14 @@ func GetClientType(client string) ClientType {
 
 type IClient interface {
 	Start() error
+	Spec() ClientSpec
 }
 
 type (
 	StarkNodeKitConfig struct {
 		Network                string          `yaml:"network"`
-		Wallet                 WalletConfig    `yaml:"wallet,omitempty"`
+		Wallet                 WalletConfig    `yaml:"wallet,omitempty"`  // active wallet, used for staking
+		Wallets                []WalletConfig  `yaml:"wallets,omitempty"` // other imported wallets
 		IsValidatorNode        bool            `yaml:"is_validator_node,omitempty"`
 		ExecutionCientSettings ClientConfig    `yaml:"execution_client"`
 		ConsensusCientSettings ClientConfig    `yaml:"consensus_client"`
@@ -46,16 +50,30 @@ type (
 	}
 
 	ClientConfig struct {
-		ExecutionType       string     `yaml:"execution_type,omitempty"`
-		Port                []int      `yaml:"ports"`
-		ConsensusCheckpoint string     `yaml:"consensus_checkpoint,omitempty"`
-		Name                ClientType `yaml:"name"`
+		ExecutionType       string          `yaml:"execution_type,omitempty"`
+		Port                []int           `yaml:"ports"`
+		ConsensusCheckpoint string          `yaml:"consensus_checkpoint,omitempty"`
+		Name                ClientType      `yaml:"name"`
+		StopTimeout         time.Duration   `yaml:"stop_timeout,omitempty"`
+		Logs                LogConfig       `yaml:"logs,omitempty"`
+		Listen              PortsConfig     `yaml:"listen,omitempty"`     // RPC and metrics listeners, Port holds the P2P ports
+		ExtraArgs           []string        `yaml:"extra_args,omitempty"` // override generated flags of the same name
+		Env                 []string        `yaml:"env,omitempty"`        // KEY=VALUE pairs added to the client's environment
+		Hooks               HooksConfig     `yaml:"hooks,omitempty"`
+		Resources           ResourcesConfig `yaml:"resources,omitempty"`
 	}
 
 	JunoConfig struct {
-		Port        int      `yaml:"port"`
-		EthNode     string   `yaml:"eth_node"`
-		Environment []string `yaml:"environment"` // NOTE currently not being used
+		Port        int             `yaml:"port"` // HTTP RPC port, takes precedence over Listen.HTTP
+		EthNode     string          `yaml:"eth_node"`
+		Environment []string        `yaml:"environment,omitempty"` // Deprecated: use Env, still applied before it
+		StopTimeout time.Duration   `yaml:"stop_timeout,omitempty"`
+		Logs        LogConfig       `yaml:"logs,omitempty"`
+		Listen      PortsConfig     `yaml:"listen,omitempty"`
+		ExtraArgs   []string        `yaml:"extra_args,omitempty"`
+		Env         []string        `yaml:"env,omitempty"`
+		Hooks       HooksConfig     `yaml:"hooks,omitempty"`
+		Resources   ResourcesConfig `yaml:"resources,omitempty"`
 	}
 
 	WalletConfig struct {
@@ -70,27 +88,79 @@ type (
 		ClassHash  string `json:"class_hash"`
 		Deployed   bool   `json:"deployed"`
 		Legacy     bool   `json:"legacy"`
-		PrivateKey string `json:"private_key"`
+		PrivateKey string `json:"private_key" yaml:"privatekey,omitempty"` // only wallets created before keystores
 		PublicKey  string `json:"public_key"`
 		Salt       string `json:"salt"`
+		Keystore   string `json:"keystore,omitempty" yaml:"keystore,omitempty"` // encrypted private key
+		Kind       string `json:"kind,omitempty" yaml:"kind,omitempty"`         // account contract family: openzeppelin, argent or braavos
 	}
 
 	ValidatorConfig struct {
-		ProviderConfig struct {
-			JunoRPC string `json:"http" yaml:"juno_rpc_http"`
-			JunoWS  string `json:"ws" yaml:"juno_rpc_ws"`
-		} `json:"provider" yaml:"provider_config"`
 		SignerConfig struct {
 			OperationalAddress string `json:"operational_address"`
-			WalletPrivateKey   string `json:"privateKey"`
+			WalletPrivateKey   string `json:"privateKey" yaml:"walletprivatekey,omitempty"`
+			Keystore           string `json:"-" yaml:"keystore,omitempty"`
 		} `json:"signer" yaml:"signer"`
+		StopTimeout time.Duration   `json:"-" yaml:"stop_timeout,omitempty"`
+		Logs        LogConfig       `json:"-" yaml:"logs,omitempty"`
+		ExtraArgs   []string        `json:"-" yaml:"extra_args,omitempty"`
+		Env         []string        `json:"-" yaml:"env,omitempty"`
+		Hooks       HooksConfig     `json:"-" yaml:"hooks,omitempty"`
+		Resources   ResourcesConfig `json:"-" yaml:"resources,omitempty"`
+	}
+
+	// PortsConfig lists the RPC and metrics listeners of a client. Zero values
+	// use the client's defaults, see DefaultPorts.
+	PortsConfig struct {
+		HTTP           int    `yaml:"http,omitempty"`     // JSON-RPC (execution, Juno) or beacon API (consensus)
+		WS             int    `yaml:"ws,omitempty"`       // Juno websocket RPC
+		AuthRPC        int    `yaml:"auth_rpc,omitempty"` // engine API of the execution client
+		GRPC           int    `yaml:"grpc,omitempty"`     // Prysm gRPC API
+		Metrics        int    `yaml:"metrics,omitempty"`
+		BindAddress    string `yaml:"bind_address,omitempty"`    // address of the RPC listeners
+		MetricsAddress string `yaml:"metrics_address,omitempty"` // address of the metrics listener
+	}
+
+	// HooksConfig holds shell commands run around lifecycle events of a client.
+	// A failing pre_* hook aborts the action.
+	HooksConfig struct {
+		PreStart   string        `yaml:"pre_start,omitempty"`
+		PostStart  string        `yaml:"post_start,omitempty"`
+		PreStop    string        `yaml:"pre_stop,omitempty"`
+		PostStop   string        `yaml:"post_stop,omitempty"`
+		PreUpdate  string        `yaml:"pre_update,omitempty"`
+		PostUpdate string        `yaml:"post_update,omitempty"`
+		Timeout    time.Duration `yaml:"timeout,omitempty"` // per hook, 5m by default
+	}
+
+	// ResourcesConfig limits the resources of a client. Zero values leave the
+	// inherited settings untouched. MemoryMaxMB and CPUWeight are enforced by
+	// cgroup v2 and need Cgroup to point at a cgroup delegated to this user.
+	ResourcesConfig struct {
+		MemoryMaxMB int    `yaml:"memory_max_mb,omitempty"` // memory.max of the client's cgroup
+		CPUWeight   int    `yaml:"cpu_weight,omitempty"`    // cpu.weight of the client's cgroup, 1-10000, 100 is the default
+		Nice        int    `yaml:"nice,omitempty"`          // -20 (highest priority) to 19
+		IOClass     string `yaml:"io_class,omitempty"`      // realtime, best-effort or idle
+		IOPriority  int    `yaml:"io_priority,omitempty"`   // 0 (highest) to 7 within the IO class
+		OpenFiles   uint64 `yaml:"open_files,omitempty"`    // RLIMIT_NOFILE
+		Cgroup      string `yaml:"cgroup,omitempty"`        // delegated cgroup v2 directory, the client runs in a child named after it
+	}
+
+	// LogConfig limits the size and number of a client's log files. Zero values use the defaults.
+	LogConfig struct {
+		MaxSizeMB  int           `yaml:"max_size_mb,omitempty"` // rotate once the active file reaches this size
+		MaxAge     time.Duration `yaml:"max_age,omitempty"`     // rotate once the active file is this old
+		MaxBackups int           `yaml:"max_backups,omitempty"` // rotated files to keep
+		Compress   *bool         `yaml:"compress,omitempty"`    // gzip rotated files, on by default
 	}
 )
 
 func (c *Wallet) Normalize() {
 	c.Address = "${STARKNET_WALLET}"
 	c.ClassHash = "${STARKNET_CLASS_HASH}"
-	c.PrivateKey = "${STARKNET_PRIVATE_KEY}"
+	if c.Keystore == "" {
+		c.PrivateKey = "${STARKNET_PRIVATE_KEY}"
+	}
 	c.PublicKey = "${STARKNET_PUBLIC_KEY}"
 	c.Salt = "${STARKNET_SALT}"
 }
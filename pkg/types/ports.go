@@ -0,0 +1,124 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+)
+
+// defaultPorts are the listeners each client used before they were configurable
+var defaultPorts = map[ClientType]PortsConfig{
+	ClientGeth:       {HTTP: 8545, AuthRPC: 8551, Metrics: 7878, BindAddress: "0.0.0.0", MetricsAddress: "0.0.0.0"},
+	ClientReth:       {HTTP: 8545, AuthRPC: 8551, Metrics: 7878, BindAddress: "0.0.0.0", MetricsAddress: "0.0.0.0"},
+	ClientLighthouse: {HTTP: 5052, Metrics: 5054, BindAddress: "127.0.0.1", MetricsAddress: "127.0.0.1"},
+	ClientPrysm:      {HTTP: 5052, GRPC: 4000, Metrics: 5054, BindAddress: "0.0.0.0", MetricsAddress: "127.0.0.1"},
+	ClientJuno:       {HTTP: 6060, WS: 6061, BindAddress: "0.0.0.0"},
+}
+
+// DefaultPorts returns the default listeners of a client
+func DefaultPorts(client ClientType) PortsConfig {
+	return defaultPorts[client]
+}
+
+// legacyConsensusPorts are the P2P ports `config new` used to write for the
+// consensus client. Their first port is the beacon API's, so they conflict.
+var legacyConsensusPorts = []int{5052, 9000}
+
+// MigrateLegacyPorts replaces the consensus P2P ports written by older
+// versions, which clash with the default beacon API port, with the current
+// defaults
+func (c *StarkNodeKitConfig) MigrateLegacyPorts() {
+	settings := &c.ConsensusCientSettings
+	if slices.Equal(settings.Port, legacyConsensusPorts) && settings.Ports().HTTP == legacyConsensusPorts[0] {
+		settings.Port = []int{9000, 9001}
+	}
+}
+
+// Ports returns the listeners of a configured client with defaults filled in
+func (c StarkNodeKitConfig) Ports(client ClientType) PortsConfig {
+	switch client {
+	case c.ExecutionCientSettings.Name:
+		return c.ExecutionCientSettings.Ports()
+	case c.ConsensusCientSettings.Name:
+		return c.ConsensusCientSettings.Ports()
+	case ClientJuno:
+		return c.JunoConfig.Ports()
+	default:
+		return DefaultPorts(client)
+	}
+}
+
+// Ports returns the listeners of the client with defaults filled in
+func (c ClientConfig) Ports() PortsConfig {
+	return c.Listen.WithDefaults(c.Name)
+}
+
+// Ports returns Juno's listeners with defaults filled in
+func (c JunoConfig) Ports() PortsConfig {
+	ports := c.Listen
+	if c.Port != 0 {
+		ports.HTTP = c.Port
+	}
+	return ports.WithDefaults(ClientJuno)
+}
+
+// WithDefaults fills unset listeners with the defaults of a client
+func (p PortsConfig) WithDefaults(client ClientType) PortsConfig {
+	defaults := DefaultPorts(client)
+	if p.HTTP == 0 {
+		p.HTTP = defaults.HTTP
+	}
+	if p.WS == 0 {
+		p.WS = defaults.WS
+	}
+	if p.AuthRPC == 0 {
+		p.AuthRPC = defaults.AuthRPC
+	}
+	if p.GRPC == 0 {
+		p.GRPC = defaults.GRPC
+	}
+	if p.Metrics == 0 {
+		p.Metrics = defaults.Metrics
+	}
+	if p.BindAddress == "" {
+		p.BindAddress = defaults.BindAddress
+	}
+	if p.MetricsAddress == "" {
+		p.MetricsAddress = defaults.MetricsAddress
+	}
+	return p
+}
+
+// HTTPURL is the URL local tools use to reach the HTTP listener
+func (p PortsConfig) HTTPURL() string {
+	return fmt.Sprintf("http://%s", p.dialAddress(p.BindAddress, p.HTTP))
+}
+
+// WSURL is the URL local tools use to reach the websocket listener
+func (p PortsConfig) WSURL() string {
+	return fmt.Sprintf("ws://%s", p.dialAddress(p.BindAddress, p.WS))
+}
+
+// AuthRPCURL is the URL a local consensus client uses to reach the engine API
+func (p PortsConfig) AuthRPCURL() string {
+	return fmt.Sprintf("http://%s", p.dialAddress(p.BindAddress, p.AuthRPC))
+}
+
+// AuthRPCAddress is the host:port of the engine API
+func (p PortsConfig) AuthRPCAddress() string {
+	return p.dialAddress(p.BindAddress, p.AuthRPC)
+}
+
+// MetricsURL is the URL local tools use to reach the metrics listener
+func (p PortsConfig) MetricsURL() string {
+	return fmt.Sprintf("http://%s", p.dialAddress(p.MetricsAddress, p.Metrics))
+}
+
+// dialAddress turns a bind address into one that can be connected to locally
+func (PortsConfig) dialAddress(bind string, port int) string {
+	if ip := net.ParseIP(bind); bind == "" || (ip != nil && ip.IsUnspecified()) {
+		bind = "localhost"
+	}
+	return net.JoinHostPort(bind, strconv.Itoa(port))
+}
@@ -3,12 +3,67 @@ package types
 import "time"
 
 type ProcessInfo struct {
-	PID      int           `json:"pid"`
-	Name     string        `json:"name"`
-	Status   string        `json:"status"`
-	Uptime   time.Duration `json:"uptime"`
-	CPUUsage float64       `json:"cpu_usage"`
-	MemUsage uint64        `json:"mem_usage"`
+	PID       int           `json:"pid"`
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	Uptime    time.Duration `json:"uptime"`
+	CPUUsage  float64       `json:"cpu_usage"`  // percent of one core, summed over the process tree
+	MemUsage  uint64        `json:"mem_usage"`  // resident set size in bytes
+	DiskRead  uint64        `json:"disk_read"`  // bytes read from storage since start
+	DiskWrite uint64        `json:"disk_write"` // bytes written to storage since start
+	OpenFDs   int           `json:"open_fds"`
+	Processes int           `json:"processes"` // the client process plus its children
+
+	// Limits applied to the client, see ResourcesConfig
+	Nice         int    `json:"nice"`
+	IOClass      string `json:"io_class,omitempty"`
+	FDLimit      uint64 `json:"fd_limit,omitempty"`      // soft RLIMIT_NOFILE of the client process
+	Cgroup       string `json:"cgroup,omitempty"`        // cgroup v2 path of the client process
+	CgroupMemory uint64 `json:"cgroup_memory,omitempty"` // memory.current of the cgroup
+	MemLimit     uint64 `json:"mem_limit,omitempty"`     // memory.max of the cgroup, 0 if unlimited
+	CPUWeight    int    `json:"cpu_weight,omitempty"`    // cpu.weight of the cgroup
+}
+
+// ClientSpec is the fully resolved launch description of a client process
+type ClientSpec struct {
+	Name        string
+	Command     string
+	Args        []string
+	Env         []string // added to the inherited environment, later entries win
+	LogDir      string
+	Logs        LogConfig
+	StopTimeout time.Duration
+	Resources   ResourcesConfig
+	Files       []ClientFile // written before every launch, removed once read
+}
+
+// ClientFile is a file a client reads at startup, e.g. a config holding a
+// secret that must not appear on the command line. It is written with mode
+// 0600 to a tmpfs and removed as soon as the client has read it or exited.
+type ClientFile struct {
+	Path string
+	// Data renders the content right before launch, so secrets are only
+	// decrypted when the client is actually started
+	Data func() ([]byte, error)
+}
+
+// StopResult reports how a client was stopped
+type StopResult struct {
+	PID      int
+	Method   string // "terminated" (exited after SIGTERM), "killed" (needed SIGKILL) or "exited"
+	Duration time.Duration
+}
+
+// ProcessRecord is the registry entry written for every client launched by starknode-kit
+type ProcessRecord struct {
+	Name       string    `json:"name"`
+	PID        int       `json:"pid"`
+	StartTicks uint64    `json:"start_ticks"` // starttime field of /proc/<pid>/stat
+	StartedAt  time.Time `json:"started_at"`
+	Binary     string    `json:"binary"`
+	Args       []string  `json:"args"`
+	LogFile    string    `json:"log_file,omitempty"`
+	ConfigHash string    `json:"config_hash"`
 }
 
 // EthereumMetrics holds blockchain metrics
@@ -38,3 +93,23 @@ type SyncInfo struct {
 	SyncPercent  float64
 	PeersCount   int
 }
+
+// ExitRecord describes one exit of a supervised client
+type ExitRecord struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	ExitedAt  time.Time `json:"exited_at"`
+	ExitCode  int       `json:"exit_code"`
+	Signal    string    `json:"signal,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Stopped   string    `json:"stopped,omitempty"` // "terminated" or "killed" when stopped on request
+	LogTail   []string  `json:"log_tail,omitempty"`
+}
+
+// ClientHistory is the supervisor's restart history for a single client
+type ClientHistory struct {
+	Name     string       `json:"name"`
+	State    string       `json:"state"` // "running", "backoff", "crash-loop", "stopped"
+	Restarts int          `json:"restarts"`
+	Exits    []ExitRecord `json:"exits"`
+}
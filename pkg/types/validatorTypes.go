@@ -1,10 +1,45 @@
 package types
 
+import "time"
+
 type (
 	ValidatorInfo struct {
 		RewardAddress      string
 		OperationalAddress string
 		TotalStaked        float64
 		UnclaimedRewards   float64
+		// UnstakeTime is when the stake can be withdrawn, zero without an unstake intent
+		UnstakeTime time.Time
+	}
+
+	// ClaimRewardsResult is the outcome of a claim_rewards transaction. Amounts
+	// are in STRK.
+	ClaimRewardsResult struct {
+		StakerAddress       string  `json:"staker_address"`
+		RewardAddress       string  `json:"reward_address"`
+		UnclaimedBefore     float64 `json:"unclaimed_before"`
+		UnclaimedAfter      float64 `json:"unclaimed_after"`
+		RewardBalanceBefore float64 `json:"reward_balance_before"`
+		RewardBalanceAfter  float64 `json:"reward_balance_after"`
+		EstimatedFee        float64 `json:"estimated_fee"`
+		TransactionHash     string  `json:"transaction_hash,omitempty"`
+		DryRun              bool    `json:"dry_run"`
+	}
+
+	// PoolInfo is the staker's STRK delegation pool. Amounts are in STRK and
+	// the commission in percent.
+	PoolInfo struct {
+		PoolAddress    string
+		TokenAddress   string
+		TotalDelegated float64
+		Commission     float64
+	}
+
+	// PoolMember is a delegator of a pool. Amounts are in STRK.
+	PoolMember struct {
+		Address          string
+		RewardAddress    string
+		Amount           float64
+		UnclaimedRewards float64
 	}
 )
@@ -2,9 +2,11 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/keystore"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -146,7 +148,9 @@ func executeDeployment(accnt *account.Account, deployTxn *rpc.BroadcastDeployAcc
 	return resp, nil
 }
 
-func DeployAccount(netowork string) (*types.Wallet, error) {
+// DeployAccount deploys a new OpenZeppelin account whose private key is
+// encrypted into the keystore of the named wallet
+func DeployAccount(netowork, name string) (*types.Wallet, error) {
 	client, err := CreateRPCProvider(netowork)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create RPC provider: %w", err)
@@ -154,6 +158,18 @@ func DeployAccount(netowork string) (*types.Wallet, error) {
 
 	ks, pub, priv := generateKeys()
 
+	// Encrypt the key before asking for funds so it can't get lost
+	fmt.Println("🔐 Choose a password to encrypt the wallet's private key.")
+	password, err := keystore.NewPassword()
+	if err != nil {
+		return nil, err
+	}
+	keystorePath := keystore.Path(name)
+	encrypted, err := keystore.Encrypt(types.Wallet{PublicKey: FormatStarknetAddress(pub)}, FormatStarknetAddress(priv), password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt private key: %w", err)
+	}
+
 	accnt, err := createAccount(client, pub, ks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create account: %w", err)
@@ -168,6 +184,17 @@ func DeployAccount(netowork string) (*types.Wallet, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to build deploy transaction: %w", err)
 	}
+	encrypted.Address = FormatStarknetAddress(precomputedAddr)
+	if err := keystore.Save(keystorePath, encrypted); err != nil {
+		return nil, err
+	}
+	fmt.Printf("🔐 Private key encrypted to %s\n", keystorePath)
+	// Staking right after the deployment should not ask for the password again
+	_, err = keystore.Unlock(keystorePath, password, keystore.DefaultSessionTTL)
+	if err != nil && !errors.Is(err, keystore.ErrNoRuntimeDir) {
+		return nil, err
+	}
+
 	requiredAmount, err := displayFundingInfoAndStartMonitoring(deployTxn, precomputedAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start funding monitoring: %w", err)
@@ -185,13 +212,13 @@ func DeployAccount(netowork string) (*types.Wallet, error) {
 	fmt.Printf("📍 Contract address: %v\n", FormatStarknetAddress(resp.ContractAddress))
 
 	// Set all wallet-related environment variables for validator configuration
-	// These variables will be used in the config YAML with ${VAR_NAME} syntax
+	// These variables will be used in the config YAML with ${VAR_NAME} syntax.
+	// The private key is only kept in the keystore.
 	walletKS := map[string]string{
-		"STARKNET_WALLET":      FormatStarknetAddress(resp.ContractAddress), // Wallet contract address
-		"STARKNET_CLASS_HASH":  FormatStarknetAddress(classHash),            // Account contract class hash
-		"STARKNET_PRIVATE_KEY": FormatStarknetAddress(priv),                 // Private key for signing
-		"STARKNET_PUBLIC_KEY":  FormatStarknetAddress(pub),                  // Public key derived from private key
-		"STARKNET_SALT":        FormatStarknetAddress(pub),                  // Salt used for deployment (using pub as salt)
+		"STARKNET_WALLET":     FormatStarknetAddress(resp.ContractAddress), // Wallet contract address
+		"STARKNET_CLASS_HASH": FormatStarknetAddress(classHash),            // Account contract class hash
+		"STARKNET_PUBLIC_KEY": FormatStarknetAddress(pub),                  // Public key derived from private key
+		"STARKNET_SALT":       FormatStarknetAddress(pub),                  // Salt used for deployment (using pub as salt)
 	}
 	err = writeToENV(walletKS)
 	if err != nil {
@@ -202,13 +229,13 @@ func DeployAccount(netowork string) (*types.Wallet, error) {
 
 	// Create and return the Wallet struct
 	wallet := &types.Wallet{
-		Address:    FormatStarknetAddress(resp.ContractAddress),
-		ClassHash:  FormatStarknetAddress(classHash),
-		Deployed:   true,
-		Legacy:     false,
-		PrivateKey: FormatStarknetAddress(priv),
-		PublicKey:  FormatStarknetAddress(pub),
-		Salt:       FormatStarknetAddress(pub), // Using pub as salt
+		Address:   FormatStarknetAddress(resp.ContractAddress),
+		ClassHash: FormatStarknetAddress(classHash),
+		Deployed:  true,
+		Legacy:    false,
+		PublicKey: FormatStarknetAddress(pub),
+		Salt:      FormatStarknetAddress(pub), // Using pub as salt
+		Keystore:  keystorePath,
 	}
 	transactionUrl := fmt.Sprintf("https://sepolia.voyager.online/tx/%s", FormatTransactionHash(resp.Hash))
 	fmt.Println("Transaction successfull, view here: ", transactionUrl)
@@ -216,5 +243,4 @@ func DeployAccount(netowork string) (*types.Wallet, error) {
 	return wallet, nil
 }
 
-// TODO delegation pool
 // NOTE check balance may return early before the transaction is complete
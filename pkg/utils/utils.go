@@ -78,12 +78,14 @@ func LoadConfig() (t.StarkNodeKitConfig, error) {
 		if err != nil {
 			return t.StarkNodeKitConfig{}, err
 		}
+		cfg.MigrateLegacyPorts()
 		return cfg, nil
 	}
 	err = yaml.Unmarshal(cfgByt, &cfg)
 	if err != nil {
 		return t.StarkNodeKitConfig{}, err
 	}
+	cfg.MigrateLegacyPorts()
 	return cfg, nil
 }
 
@@ -95,11 +97,12 @@ func UpdateStarkNodeConfig(config t.StarkNodeKitConfig) error {
 	if err != nil {
 		return err
 	}
-	err = os.WriteFile(constants.ConfigPath, cfg, 0600)
-	if err != nil {
+	// Replace the file in one step so a failed write can't truncate it
+	tmp := constants.ConfigPath + ".tmp"
+	if err := os.WriteFile(tmp, cfg, 0600); err != nil {
 		return err
 	}
-	return nil
+	return os.Rename(tmp, constants.ConfigPath)
 }
 
 func CreateStarkNodeConfig(cfg *types.StarkNodeKitConfig) error {
@@ -133,6 +136,8 @@ func CreateStarkNodeConfig(cfg *types.StarkNodeKitConfig) error {
 
 func GetRunningClients() []types.ClientStatus {
 	var clients []types.ClientStatus
+	// Without a config the default endpoints are used
+	cfg, _ := LoadConfig()
 
 	// Check for Geth
 	if gethInfo := process.GetProcessInfo("geth"); gethInfo != nil {
@@ -142,7 +147,7 @@ func GetRunningClients() []types.ClientStatus {
 			PID:        gethInfo.PID,
 			Uptime:     gethInfo.Uptime,
 			Version:    versions.GetVersionNumber("geth"),
-			SyncStatus: GetGethSyncStatus(),
+			SyncStatus: GetGethSyncStatus(cfg.Ports(types.ClientGeth).HTTPURL()),
 		}
 		clients = append(clients, status)
 	}
@@ -155,7 +160,7 @@ func GetRunningClients() []types.ClientStatus {
 			PID:        rethInfo.PID,
 			Uptime:     rethInfo.Uptime,
 			Version:    versions.GetVersionNumber("reth"),
-			SyncStatus: GetRethSyncStatus(),
+			SyncStatus: GetRethSyncStatus(cfg.Ports(types.ClientReth).HTTPURL()),
 		}
 		clients = append(clients, status)
 	}
@@ -168,7 +173,7 @@ func GetRunningClients() []types.ClientStatus {
 			PID:        lighthouseInfo.PID,
 			Uptime:     lighthouseInfo.Uptime,
 			Version:    versions.GetVersionNumber("lighthouse"),
-			SyncStatus: GetLighthouseSyncStatus(),
+			SyncStatus: GetLighthouseSyncStatus(cfg.Ports(types.ClientLighthouse).HTTPURL()),
 		}
 		clients = append(clients, status)
 	}
@@ -181,7 +186,7 @@ func GetRunningClients() []types.ClientStatus {
 			PID:        prysmInfo.PID,
 			Uptime:     prysmInfo.Uptime,
 			Version:    versions.GetVersionNumber("prysm"),
-			SyncStatus: GetPrysmSyncStatus(),
+			SyncStatus: GetPrysmSyncStatus(cfg.Ports(types.ClientPrysm).HTTPURL()),
 		}
 		clients = append(clients, status)
 	}
@@ -16,7 +16,8 @@ import (
 	"github.com/joho/godotenv"
 )
 
-func GetGethSyncStatus() t.SyncInfo {
+// GetGethSyncStatus gets sync status from an execution client's JSON-RPC API
+func GetGethSyncStatus(rpcURL string) t.SyncInfo {
 	syncInfo := t.SyncInfo{IsSyncing: false, SyncPercent: 100.0}
 
 	// Try to get sync status from Geth's HTTP API
@@ -24,7 +25,7 @@ func GetGethSyncStatus() t.SyncInfo {
 
 	// Geth eth_syncing call
 	payload := `{"jsonrpc":"2.0","method":"eth_syncing","params":[],"id":1}`
-	resp, err := client.Post("http://localhost:8545", "application/json", strings.NewReader(payload))
+	resp, err := client.Post(rpcURL, "application/json", strings.NewReader(payload))
 	if err != nil {
 		return syncInfo
 	}
@@ -67,7 +68,7 @@ func GetGethSyncStatus() t.SyncInfo {
 
 	// Get peer count
 	peerPayload := `{"jsonrpc":"2.0","method":"net_peerCount","params":[],"id":2}`
-	peerResp, err := client.Post("http://localhost:8545", "application/json", strings.NewReader(peerPayload))
+	peerResp, err := client.Post(rpcURL, "application/json", strings.NewReader(peerPayload))
 	if err == nil {
 		defer peerResp.Body.Close()
 		peerBody, _ := io.ReadAll(peerResp.Body)
@@ -85,19 +86,19 @@ func GetGethSyncStatus() t.SyncInfo {
 }
 
 // getRethSyncStatus gets sync status from Reth's HTTP API
-func GetRethSyncStatus() t.SyncInfo {
+func GetRethSyncStatus(rpcURL string) t.SyncInfo {
 	// Similar to Geth but Reth might have different endpoints
-	return GetGethSyncStatus() // For now, use same logic
+	return GetGethSyncStatus(rpcURL) // For now, use same logic
 }
 
 // getLighthouseSyncStatus gets sync status from Lighthouse's HTTP API
-func GetLighthouseSyncStatus() t.SyncInfo {
+func GetLighthouseSyncStatus(beaconURL string) t.SyncInfo {
 	syncInfo := t.SyncInfo{IsSyncing: false, SyncPercent: 100.0}
 
 	client := &http.Client{Timeout: 2 * time.Second}
 
 	// Lighthouse HTTP API
-	resp, err := client.Get("http://localhost:5052/eth/v1/node/syncing")
+	resp, err := client.Get(beaconURL + "/eth/v1/node/syncing")
 	if err != nil {
 		return syncInfo
 	}
@@ -138,13 +139,13 @@ func GetLighthouseSyncStatus() t.SyncInfo {
 }
 
 // getPrysmSyncStatus gets sync status from Prysm's HTTP API
-func GetPrysmSyncStatus() t.SyncInfo {
+func GetPrysmSyncStatus(beaconURL string) t.SyncInfo {
 	syncInfo := t.SyncInfo{IsSyncing: false, SyncPercent: 100.0}
 
 	client := &http.Client{Timeout: 2 * time.Second}
 
 	// Prysm HTTP API
-	resp, err := client.Get("http://localhost:5052/eth/v1/node/syncing")
+	resp, err := client.Get(beaconURL + "/eth/v1/node/syncing")
 	if err != nil {
 		return syncInfo
 	}
@@ -185,7 +186,7 @@ func GetPrysmSyncStatus() t.SyncInfo {
 	return syncInfo
 }
 
-func GetJunoMetrics(network string) t.EthereumMetrics {
+func GetJunoMetrics(network, rpcURL string) t.EthereumMetrics {
 	metrics := t.EthereumMetrics{
 		NetworkName: network,
 		IsSyncing:   false,
@@ -196,7 +197,7 @@ func GetJunoMetrics(network string) t.EthereumMetrics {
 
 	// Get current block number
 	blockPayload := `{"jsonrpc":"2.0","method":"starknet_blockNumber","params":[],"id":1}`
-	resp, err := client.Post("http://localhost:6060", "application/json", strings.NewReader(blockPayload))
+	resp, err := client.Post(rpcURL, "application/json", strings.NewReader(blockPayload))
 	if err == nil {
 		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
@@ -210,7 +211,7 @@ func GetJunoMetrics(network string) t.EthereumMetrics {
 
 	// Get gas price
 	gasPricePayload := `{"jsonrpc":"2.0","method":"starknet_syncing","params":[],"id":3}`
-	gasResp, err := client.Post("http://localhost:6060", "application/json", strings.NewReader(gasPricePayload))
+	gasResp, err := client.Post(rpcURL, "application/json", strings.NewReader(gasPricePayload))
 	if err == nil {
 		defer gasResp.Body.Close()
 		syncBody, _ := io.ReadAll(gasResp.Body)
@@ -246,13 +247,13 @@ func defaultConfig() *t.StarkNodeKitConfig {
 		},
 		ConsensusCientSettings: t.ClientConfig{
 			Name:                t.ClientPrysm,
-			Port:                []int{5052, 9000},
+			Port:                []int{9000, 9001},
 			ConsensusCheckpoint: "https://mainnet-checkpoint-sync.stakely.io/",
 		},
 		JunoConfig: t.JunoConfig{
 			Port:    6060,
 			EthNode: "wss://eth.drpc.org",
-			Environment: []string{
+			Env: []string{
 				"JUNO_HTTP_PORT=6060",
 				"JUNO_HTTP_HOST=0.0.0.0",
 			},
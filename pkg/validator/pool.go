@@ -0,0 +1,251 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	starkutils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// ErrNoPool is returned when the staker has no STRK delegation pool
+var ErrNoPool = errors.New("no STRK delegation pool, run `starknode-kit validator pool open` first")
+
+// ErrNoCommission is returned when a pool is opened before the commission is set
+var ErrNoCommission = errors.New("no commission set, run `starknode-kit validator set commission <percent>` first")
+
+// eventsChunkSize is the number of events read per starknet_getEvents request
+const eventsChunkSize = 1000
+
+// stakerPool is a PoolInfo of the staker's StakerPoolInfoV2
+type stakerPool struct {
+	contract *felt.Felt
+	token    *felt.Felt
+	amount   *felt.Felt
+}
+
+// stakerPoolInfo is the StakerPoolInfoV2 returned by staker_pool_info
+type stakerPoolInfo struct {
+	commission *felt.Felt // basis points, nil if never set
+	pools      []stakerPool
+}
+
+// OpenPool opens the staker's STRK delegation pool and returns its address.
+// The commission must have been set before.
+func OpenPool(rpcProvider *rpc.Provider, wallet types.Wallet) (string, error) {
+	info, err := getStakerPoolInfo(rpcProvider, wallet)
+	if err != nil {
+		return "", err
+	}
+	if pool, err := strkPool(info); err == nil {
+		return pool.PoolAddress, fmt.Errorf("the STRK delegation pool is already open at %s", pool.PoolAddress)
+	}
+	// The staking contract reverts without one, don't pay for the estimate
+	if info.commission == nil {
+		return "", ErrNoCommission
+	}
+
+	strkAddr, err := starkutils.HexToFelt(constants.StrkTokenAddress)
+	if err != nil {
+		return "", err
+	}
+	accnt, err := stakerAccount(rpcProvider, wallet)
+	if err != nil {
+		return "", err
+	}
+	if err := invokeStaking(rpcProvider, accnt, "set_open_for_delegation", []*felt.Felt{strkAddr}, "open the delegation pool"); err != nil {
+		return "", err
+	}
+
+	pool, err := GetPoolInfo(rpcProvider, wallet)
+	if err != nil {
+		return "", fmt.Errorf("pool opened but failed to read its address: %w", err)
+	}
+	return pool.PoolAddress, nil
+}
+
+// GetPoolInfo returns the staker's STRK delegation pool
+func GetPoolInfo(rpcProvider *rpc.Provider, wallet types.Wallet) (types.PoolInfo, error) {
+	info, err := getStakerPoolInfo(rpcProvider, wallet)
+	if err != nil {
+		return types.PoolInfo{}, err
+	}
+	return strkPool(info)
+}
+
+// getStakerPoolInfo reads the pools and commission of the wallet's staker
+func getStakerPoolInfo(rpcProvider *rpc.Provider, wallet types.Wallet) (stakerPoolInfo, error) {
+	address, err := starkutils.HexToFelt(wallet.Address)
+	if err != nil {
+		return stakerPoolInfo{}, err
+	}
+	result, err := callContract(rpcProvider, constants.StakingContract, "staker_pool_info", address)
+	if err != nil {
+		return stakerPoolInfo{}, err
+	}
+	return decodeStakerPoolInfo(result)
+}
+
+// strkPool returns the STRK pool of a staker, ErrNoPool if it has none
+func strkPool(info stakerPoolInfo) (types.PoolInfo, error) {
+	strkAddr, err := starkutils.HexToFelt(constants.StrkTokenAddress)
+	if err != nil {
+		return types.PoolInfo{}, err
+	}
+	i := slices.IndexFunc(info.pools, func(p stakerPool) bool { return p.token.Equal(strkAddr) })
+	if i < 0 {
+		return types.PoolInfo{}, ErrNoPool
+	}
+	pool := types.PoolInfo{
+		PoolAddress:    utils.FormatStarknetAddress(info.pools[i].contract),
+		TokenAddress:   utils.FormatStarknetAddress(info.pools[i].token),
+		TotalDelegated: starkutils.FRIToSTRK(info.pools[i].amount),
+	}
+	if info.commission != nil {
+		pool.Commission = float64(info.commission.Uint64()) / 100
+	}
+	return pool, nil
+}
+
+// GetPoolMembers lists the current delegators of a pool. Members are found
+// from the pool's NewPoolMember events, their balances are read from the pool.
+// Members that left the pool are skipped.
+func GetPoolMembers(rpcProvider *rpc.Provider, poolAddress string) ([]types.PoolMember, error) {
+	pool, err := starkutils.HexToFelt(poolAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pool address %s: %w", poolAddress, err)
+	}
+	addresses, err := poolMemberAddresses(rpcProvider, pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the pool's events: %w", err)
+	}
+
+	var members []types.PoolMember
+	for _, address := range addresses {
+		result, err := callContract(rpcProvider, poolAddress, "get_pool_member_info_v1", address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pool member %s: %w", utils.FormatStarknetAddress(address), err)
+		}
+		member, ok, err := decodePoolMember(address, result)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			members = append(members, member)
+		}
+	}
+	slices.SortFunc(members, func(a, b types.PoolMember) int {
+		switch {
+		case a.Amount > b.Amount:
+			return -1
+		case a.Amount < b.Amount:
+			return 1
+		}
+		return 0
+	})
+	return members, nil
+}
+
+// poolMemberAddresses returns every address that ever joined the pool, in
+// the order they joined
+func poolMemberAddresses(rpcProvider *rpc.Provider, pool *felt.Felt) ([]*felt.Felt, error) {
+	input := rpc.EventsInput{
+		EventFilter: rpc.EventFilter{
+			FromBlock: rpc.WithBlockNumber(0),
+			ToBlock:   rpc.WithBlockTag(rpc.BlockTagLatest),
+			Address:   pool,
+			Keys:      [][]*felt.Felt{{starkutils.GetSelectorFromNameFelt("NewPoolMember")}},
+		},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: eventsChunkSize},
+	}
+
+	var addresses []*felt.Felt
+	seen := make(map[felt.Felt]bool)
+	for {
+		chunk, err := rpcProvider.Events(context.Background(), input)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range chunk.Events {
+			// The pool member is the first key after the event selector
+			if len(event.Keys) < 2 || seen[*event.Keys[1]] {
+				continue
+			}
+			seen[*event.Keys[1]] = true
+			addresses = append(addresses, event.Keys[1])
+		}
+		if chunk.ContinuationToken == "" {
+			return addresses, nil
+		}
+		input.ContinuationToken = chunk.ContinuationToken
+	}
+}
+
+// decodeStakerPoolInfo decodes a StakerPoolInfoV2: an optional commission
+// followed by a span of (pool contract, token address, amount)
+func decodeStakerPoolInfo(result []*felt.Felt) (stakerPoolInfo, error) {
+	malformed := fmt.Errorf("malformed staker pool info of %d felts", len(result))
+	if len(result) < 2 {
+		return stakerPoolInfo{}, malformed
+	}
+	var info stakerPoolInfo
+	data := result
+	if data[0].Uint64() == optionSome {
+		info.commission = data[1]
+		data = data[2:]
+	} else {
+		data = data[1:]
+	}
+	if len(data) == 0 {
+		return stakerPoolInfo{}, malformed
+	}
+	count := data[0].Uint64()
+	data = data[1:]
+	if uint64(len(data)) != count*3 {
+		return stakerPoolInfo{}, malformed
+	}
+	for i := 0; i+2 < len(data); i += 3 {
+		info.pools = append(info.pools, stakerPool{contract: data[i], token: data[i+1], amount: data[i+2]})
+	}
+	return info, nil
+}
+
+// decodePoolMember decodes an Option<PoolMemberInfoV1>, reporting false for
+// an address that is no longer a member
+func decodePoolMember(address *felt.Felt, result []*felt.Felt) (types.PoolMember, bool, error) {
+	if len(result) == 0 || result[0].Uint64() == optionNone {
+		return types.PoolMember{}, false, nil
+	}
+	if len(result) < 4 {
+		return types.PoolMember{}, false, fmt.Errorf("malformed pool member info of %d felts", len(result))
+	}
+	return types.PoolMember{
+		Address:          utils.FormatStarknetAddress(address),
+		RewardAddress:    utils.FormatStarknetAddress(result[1]),
+		Amount:           starkutils.FRIToSTRK(result[2]),
+		UnclaimedRewards: starkutils.FRIToSTRK(result[3]),
+	}, true, nil
+}
+
+// callContract calls a view entry point of a contract at the latest block
+func callContract(rpcProvider *rpc.Provider, contract, entryPoint string, calldata ...*felt.Felt) ([]*felt.Felt, error) {
+	contractAddress, err := starkutils.HexToFelt(contract)
+	if err != nil {
+		return nil, err
+	}
+	if calldata == nil {
+		calldata = []*felt.Felt{}
+	}
+	txn := rpc.FunctionCall{
+		ContractAddress:    contractAddress,
+		EntryPointSelector: starkutils.GetSelectorFromNameFelt(entryPoint),
+		Calldata:           calldata,
+	}
+	return rpcProvider.Call(context.Background(), txn, rpc.BlockID{Tag: "latest"})
+}
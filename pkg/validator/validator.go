@@ -1,8 +1,11 @@
 package validator
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/rpc"
@@ -19,41 +22,27 @@ func GetValidatorInfo(rpcProvider *rpc.Provider, wallet types.Wallet) (types.Val
 		return types.ValidatorInfo{}, err
 	}
 
-	contractAddress, err := starkutils.HexToFelt(constants.StakingContract)
+	info, err := getStakerInfo(rpcProvider, address)
 	if err != nil {
 		return types.ValidatorInfo{}, err
 	}
 
-	txn := rpc.FunctionCall{
-		ContractAddress:    contractAddress,
-		EntryPointSelector: starkutils.GetSelectorFromNameFelt("get_staker_info_v1"),
-		Calldata:           []*felt.Felt{address},
-	}
-
-	result, err := rpcProvider.Call(context.Background(), txn, rpc.BlockID{Tag: "latest"})
-	if err != nil {
-		return types.ValidatorInfo{}, err
-	}
-
-	if len(result) == 1 {
-		return types.ValidatorInfo{}, fmt.Errorf("address not a validator")
-	}
-
 	return types.ValidatorInfo{
-		RewardAddress:      result[1].String(),
-		OperationalAddress: result[2].String(),
-		TotalStaked:        starkutils.FRIToSTRK(result[4]),
-		UnclaimedRewards:   starkutils.FRIToSTRK(result[5]),
+		RewardAddress:      info.rewardAddress.String(),
+		OperationalAddress: info.operationalAddress.String(),
+		TotalStaked:        starkutils.FRIToSTRK(info.amountOwn),
+		UnclaimedRewards:   starkutils.FRIToSTRK(info.unclaimedRewards),
+		UnstakeTime:        info.unstakeDeadline(),
 	}, nil
 }
 
 // GetValidatorBalance retrieves the STRK balance for a given wallet.
 func GetValidatorBalance(rpcProvider *rpc.Provider, wallet types.Wallet) (float64, error) {
-	accnt, err := newAccount(wallet, rpcProvider)
+	address, err := starkutils.HexToFelt(wallet.Address)
 	if err != nil {
 		return 0, err
 	}
-	balance, err := utils.CheckBalance(rpcProvider, accnt.Address)
+	balance, err := utils.CheckBalance(rpcProvider, address)
 	if err != nil {
 		return 0, err
 	}
@@ -84,3 +73,266 @@ func StakeStark(network string, rpcProvider *rpc.Provider, wallet types.WalletCo
 
 	return stakeAndSetCommission(network, accnt, wallet, balance)
 }
+
+// ErrNoRewards is returned by ClaimRewards when there is nothing to claim
+var ErrNoRewards = errors.New("no unclaimed rewards")
+
+// SetProgressOutput redirects the progress messages printed while transactions
+// are sent, e.g. to keep stdout for JSON output
+func SetProgressOutput(w io.Writer) {
+	progress = w
+}
+
+// ClaimRewards claims the unclaimed rewards of the wallet's staker to its
+// reward address. With dryRun the fee is only estimated and nothing is sent.
+func ClaimRewards(rpcProvider *rpc.Provider, wallet types.Wallet, dryRun bool) (types.ClaimRewardsResult, error) {
+	address, err := starkutils.HexToFelt(wallet.Address)
+	if err != nil {
+		return types.ClaimRewardsResult{}, err
+	}
+	before, err := getStakerInfo(rpcProvider, address)
+	if err != nil {
+		return types.ClaimRewardsResult{}, err
+	}
+	result := types.ClaimRewardsResult{
+		StakerAddress:   utils.FormatStarknetAddress(address),
+		RewardAddress:   utils.FormatStarknetAddress(before.rewardAddress),
+		UnclaimedBefore: starkutils.FRIToSTRK(before.unclaimedRewards),
+		DryRun:          dryRun,
+	}
+	if before.unclaimedRewards.IsZero() {
+		return result, ErrNoRewards
+	}
+	rewardBalance, err := utils.CheckBalance(rpcProvider, before.rewardAddress)
+	if err != nil {
+		return result, fmt.Errorf("failed to check the reward address balance: %w", err)
+	}
+	result.RewardBalanceBefore = starkutils.FRIToSTRK(rewardBalance)
+
+	accnt, err := newAccount(wallet, rpcProvider)
+	if err != nil {
+		return result, fmt.Errorf("failed to create account: %w", err)
+	}
+	stakingAddr, err := starkutils.HexToFelt(constants.StakingContract)
+	if err != nil {
+		return result, err
+	}
+	claimTxn := rpc.FunctionCall{
+		ContractAddress:    stakingAddr,
+		EntryPointSelector: starkutils.GetSelectorFromNameFelt("claim_rewards"),
+		Calldata:           []*felt.Felt{address},
+	}
+	invokeTxn, estimatedFee, err := estimateFee(accnt, []rpc.FunctionCall{claimTxn})
+	if err != nil {
+		return result, err
+	}
+	result.EstimatedFee = starkutils.FRIToSTRK(estimatedFee)
+	if dryRun {
+		return result, nil
+	}
+	if err := checkFunds(rpcProvider, accnt.Address, new(felt.Felt), estimatedFee, "claim rewards"); err != nil {
+		return result, err
+	}
+
+	hash, err := executeTxn(accnt, invokeTxn)
+	if err != nil {
+		return result, err
+	}
+	result.TransactionHash = utils.FormatTransactionHash(hash)
+
+	after, err := getStakerInfo(rpcProvider, address)
+	if err != nil {
+		return result, fmt.Errorf("rewards claimed but failed to read the staker info: %w", err)
+	}
+	result.UnclaimedAfter = starkutils.FRIToSTRK(after.unclaimedRewards)
+	if rewardBalance, err = utils.CheckBalance(rpcProvider, before.rewardAddress); err != nil {
+		return result, fmt.Errorf("rewards claimed but failed to check the reward address balance: %w", err)
+	}
+	result.RewardBalanceAfter = starkutils.FRIToSTRK(rewardBalance)
+	return result, nil
+}
+
+// friPerSTRK is the number of FRI, the smallest unit, in one STRK
+var friPerSTRK = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// ParseSTRK parses a positive decimal STRK amount such as "1.5" into FRI
+func ParseSTRK(amount string) (*felt.Felt, error) {
+	strk, ok := new(big.Rat).SetString(amount)
+	if !ok || strk.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid STRK amount %q", amount)
+	}
+	fri := new(big.Rat).Mul(strk, new(big.Rat).SetInt(friPerSTRK))
+	if !fri.IsInt() {
+		return nil, fmt.Errorf("STRK amount %q has more than 18 decimals", amount)
+	}
+	return starkutils.BigIntToFelt(fri.Num()), nil
+}
+
+// IncreaseStake adds amount FRI to the stake of the wallet's staker. If the
+// staking contract's allowance is too low, the approval is sent in the same
+// transaction, so nothing is sent unless the whole operation can be paid for.
+func IncreaseStake(rpcProvider *rpc.Provider, wallet types.Wallet, amount *felt.Felt) error {
+	address, err := starkutils.HexToFelt(wallet.Address)
+	if err != nil {
+		return err
+	}
+	info, err := getStakerInfo(rpcProvider, address)
+	if err != nil {
+		return err
+	}
+	if info.unstakeTime != 0 {
+		return errors.New("the staker has signaled an unstake intent, its stake can't be increased")
+	}
+
+	balance, err := utils.CheckBalance(rpcProvider, address)
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("insufficient balance to increase the stake. Have: %.6f STRK, Need: %.6f STRK", starkutils.FRIToSTRK(balance), starkutils.FRIToSTRK(amount))
+	}
+
+	accnt, err := newAccount(wallet, rpcProvider)
+	if err != nil {
+		return fmt.Errorf("failed to create account: %w", err)
+	}
+	// amount is a u128, the high half of the u256 allowance is zero
+	approveTxn, err := approveCall(accnt, rpcProvider, []*felt.Felt{amount, new(felt.Felt)})
+	if err != nil {
+		return err
+	}
+
+	stakingAddr, err := starkutils.HexToFelt(constants.StakingContract)
+	if err != nil {
+		return err
+	}
+	var calls []rpc.FunctionCall
+	if approveTxn != nil {
+		calls = append(calls, *approveTxn)
+	}
+	calls = append(calls, rpc.FunctionCall{
+		ContractAddress:    stakingAddr,
+		EntryPointSelector: starkutils.GetSelectorFromNameFelt("increase_stake"),
+		Calldata:           []*felt.Felt{address, amount},
+	})
+	invokeTxn, estimatedFee, err := estimateFee(accnt, calls)
+	if err != nil {
+		return err
+	}
+	if err := checkFunds(rpcProvider, address, amount, estimatedFee, "increase the stake"); err != nil {
+		return err
+	}
+
+	_, err = executeTxn(accnt, invokeTxn)
+	return err
+}
+
+// UnstakeIntent signals the wallet's staker intent to leave the staking
+// protocol. It returns when the stake can be withdrawn with UnstakeAction.
+func UnstakeIntent(rpcProvider *rpc.Provider, wallet types.Wallet) (time.Time, error) {
+	address, err := starkutils.HexToFelt(wallet.Address)
+	if err != nil {
+		return time.Time{}, err
+	}
+	info, err := getStakerInfo(rpcProvider, address)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if info.unstakeTime != 0 {
+		return info.unstakeDeadline(), fmt.Errorf("the unstake intent was already signaled, the stake can be withdrawn from %s", info.unstakeDeadline().Format(time.DateTime))
+	}
+
+	accnt, err := newAccount(wallet, rpcProvider)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create account: %w", err)
+	}
+	// The staker is the caller, the entry point takes no arguments
+	if err := invokeStaking(rpcProvider, accnt, "unstake_intent", nil, "signal the unstake intent"); err != nil {
+		return time.Time{}, err
+	}
+
+	info, err = getStakerInfo(rpcProvider, address)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unstake intent sent but failed to read the staker info: %w", err)
+	}
+	return info.unstakeDeadline(), nil
+}
+
+// UnstakeAction withdraws the stake of the wallet's staker once the exit
+// window after UnstakeIntent has passed. It refuses to send the transaction
+// before.
+func UnstakeAction(rpcProvider *rpc.Provider, wallet types.Wallet) error {
+	address, err := starkutils.HexToFelt(wallet.Address)
+	if err != nil {
+		return err
+	}
+	info, err := getStakerInfo(rpcProvider, address)
+	if err != nil {
+		return err
+	}
+	if info.unstakeTime == 0 {
+		return errors.New("no unstake intent, run `starknode-kit validator unstake intent` first")
+	}
+	if remaining := time.Until(info.unstakeDeadline()); remaining > 0 {
+		return fmt.Errorf("the stake can't be withdrawn before %s, %s from now", info.unstakeDeadline().Format(time.DateTime), remaining.Round(time.Second))
+	}
+
+	accnt, err := newAccount(wallet, rpcProvider)
+	if err != nil {
+		return fmt.Errorf("failed to create account: %w", err)
+	}
+	return invokeStaking(rpcProvider, accnt, "unstake_action", []*felt.Felt{address}, "withdraw the stake")
+}
+
+// SetRewardAddress changes the address the wallet's staker rewards are paid to
+func SetRewardAddress(rpcProvider *rpc.Provider, wallet types.Wallet, rewardAddress string) error {
+	reward, err := starkutils.HexToFelt(rewardAddress)
+	if err != nil {
+		return fmt.Errorf("invalid reward address %s: %w", rewardAddress, err)
+	}
+	accnt, err := stakerAccount(rpcProvider, wallet)
+	if err != nil {
+		return err
+	}
+	return invokeStaking(rpcProvider, accnt, "change_reward_address", []*felt.Felt{reward}, "change the reward address")
+}
+
+// SetCommission sets the commission, in percent, the staker takes from its
+// delegators' rewards
+func SetCommission(rpcProvider *rpc.Provider, wallet types.Wallet, percent int) error {
+	commission, err := commissionToFelt(percent)
+	if err != nil {
+		return err
+	}
+	accnt, err := stakerAccount(rpcProvider, wallet)
+	if err != nil {
+		return err
+	}
+	return invokeStaking(rpcProvider, accnt, "set_commission", []*felt.Felt{commission}, "set the commission")
+}
+
+// SetOperationalAddress makes the operational wallet sign for the staker. The
+// operational account first declares the staker it works for, then the
+// staker switches to it.
+func SetOperationalAddress(rpcProvider *rpc.Provider, staker, operational types.Wallet) error {
+	operationalAddr, err := starkutils.HexToFelt(operational.Address)
+	if err != nil {
+		return fmt.Errorf("invalid operational address %s: %w", operational.Address, err)
+	}
+	stakerAccnt, err := stakerAccount(rpcProvider, staker)
+	if err != nil {
+		return err
+	}
+
+	operationalAccnt, err := newAccount(operational, rpcProvider)
+	if err != nil {
+		return fmt.Errorf("failed to create the operational account: %w", err)
+	}
+	fmt.Fprintln(progress, utils.Cyan("Declaring the staker from the operational account..."))
+	if err := invokeStaking(rpcProvider, operationalAccnt, "declare_operational_address", []*felt.Felt{stakerAccnt.Address}, "declare the operational address"); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(progress, utils.Cyan("Switching the staker to the operational address..."))
+	return invokeStaking(rpcProvider, stakerAccnt, "change_operational_address", []*felt.Felt{operationalAddr}, "change the operational address")
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"os"
 	"strconv"
 	"time"
 
@@ -13,19 +15,154 @@ import (
 	"github.com/NethermindEth/starknet.go/rpc"
 	starkutils "github.com/NethermindEth/starknet.go/utils"
 	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/keystore"
 	"github.com/thebuidl-grid/starknode-kit/pkg/types"
 	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
 )
 
-// newAccount creates a new Starknet account instance.
+// progress receives the messages printed while transactions are built and sent
+var progress io.Writer = os.Stdout
+
+// Cairo serializes the variant of an Option before its value
+const (
+	optionSome = 0
+	optionNone = 1
+)
+
+var errNotStaker = errors.New("address not a validator")
+
+// stakerInfo is the StakerInfoV1 returned by get_staker_info_v1
+type stakerInfo struct {
+	rewardAddress      *felt.Felt
+	operationalAddress *felt.Felt
+	unstakeTime        uint64 // unix time the stake can be withdrawn, 0 without an unstake intent
+	amountOwn          *felt.Felt
+	unclaimedRewards   *felt.Felt
+}
+
+// unstakeDeadline returns when the stake can be withdrawn, zero without an unstake intent
+func (info stakerInfo) unstakeDeadline() time.Time {
+	if info.unstakeTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(info.unstakeTime), 0)
+}
+
+// getStakerInfo reads the staker info of an address from the staking contract
+func getStakerInfo(rpcProvider *rpc.Provider, address *felt.Felt) (stakerInfo, error) {
+	contractAddress, err := starkutils.HexToFelt(constants.StakingContract)
+	if err != nil {
+		return stakerInfo{}, err
+	}
+
+	txn := rpc.FunctionCall{
+		ContractAddress:    contractAddress,
+		EntryPointSelector: starkutils.GetSelectorFromNameFelt("get_staker_info_v1"),
+		Calldata:           []*felt.Felt{address},
+	}
+
+	result, err := rpcProvider.Call(context.Background(), txn, rpc.BlockID{Tag: "latest"})
+	if err != nil {
+		return stakerInfo{}, err
+	}
+	return decodeStakerInfo(result)
+}
+
+// decodeStakerInfo decodes an Option<StakerInfoV1>. The optional unstake time
+// takes one or two felts, so the amounts that follow it have no fixed offset.
+func decodeStakerInfo(result []*felt.Felt) (stakerInfo, error) {
+	if len(result) == 0 || result[0].Uint64() == optionNone {
+		return stakerInfo{}, errNotStaker
+	}
+	malformed := fmt.Errorf("malformed staker info of %d felts", len(result))
+	data := result[1:]
+	if len(data) < 5 {
+		return stakerInfo{}, malformed
+	}
+	info := stakerInfo{rewardAddress: data[0], operationalAddress: data[1]}
+	data = data[2:]
+	if data[0].Uint64() == optionSome {
+		info.unstakeTime = data[1].Uint64()
+		data = data[2:]
+	} else {
+		data = data[1:]
+	}
+	if len(data) < 2 {
+		return stakerInfo{}, malformed
+	}
+	info.amountOwn = data[0]
+	info.unclaimedRewards = data[1]
+	return info, nil
+}
+
+// checkFunds fails if the account can't pay amount plus fee
+func checkFunds(rpcProvider *rpc.Provider, address, amount, fee *felt.Felt, action string) error {
+	balance, err := utils.CheckBalance(rpcProvider, address)
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	required := new(felt.Felt).Add(amount, fee)
+	if balance.Cmp(required) < 0 {
+		return fmt.Errorf("insufficient balance to %s. Have: %.6f STRK, Need: %.6f STRK", action, starkutils.FRIToSTRK(balance), starkutils.FRIToSTRK(required))
+	}
+	return nil
+}
+
+// stakerAccount returns the account of the wallet, failing if it is not a staker
+func stakerAccount(rpcProvider *rpc.Provider, wallet types.Wallet) (*account.Account, error) {
+	address, err := starkutils.HexToFelt(wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := getStakerInfo(rpcProvider, address); err != nil {
+		return nil, err
+	}
+	accnt, err := newAccount(wallet, rpcProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+	return accnt, nil
+}
+
+// invokeStaking sends a single call to the staking contract, paid by accnt
+func invokeStaking(rpcProvider *rpc.Provider, accnt *account.Account, entryPoint string, calldata []*felt.Felt, action string) error {
+	stakingAddr, err := starkutils.HexToFelt(constants.StakingContract)
+	if err != nil {
+		return err
+	}
+	if calldata == nil {
+		calldata = []*felt.Felt{}
+	}
+	call := rpc.FunctionCall{
+		ContractAddress:    stakingAddr,
+		EntryPointSelector: starkutils.GetSelectorFromNameFelt(entryPoint),
+		Calldata:           calldata,
+	}
+	invokeTxn, estimatedFee, err := estimateFee(accnt, []rpc.FunctionCall{call})
+	if err != nil {
+		return err
+	}
+	if err := checkFunds(rpcProvider, accnt.Address, new(felt.Felt), estimatedFee, action); err != nil {
+		return err
+	}
+	_, err = executeTxn(accnt, invokeTxn)
+	return err
+}
+
+// newAccount creates a new Starknet account instance. The private key is
+// decrypted from the wallet's keystore, so only call it to sign.
 func newAccount(wallet types.Wallet, rpcProvider *rpc.Provider) (*account.Account, error) {
 	userWalletAddress, err := starkutils.HexToFelt(wallet.Address)
 	if err != nil {
 		return nil, err
 	}
 
+	privateKey, err := keystore.WalletKey(wallet)
+	if err != nil {
+		return nil, err
+	}
 	ks := account.NewMemKeystore()
-	privKeyBI, ok := new(big.Int).SetString(wallet.PrivateKey, 0)
+	privKeyBI, ok := new(big.Int).SetString(privateKey, 0)
 	if !ok {
 		return nil, fmt.Errorf("failed to convert private key to big.Int")
 	}
@@ -42,45 +179,60 @@ func newAccount(wallet types.Wallet, rpcProvider *rpc.Provider) (*account.Accoun
 
 // approveStakes checks allowance and approves the staking contract to spend STRK if necessary.
 func approveStakes(network string, accnt *account.Account, rpcProvider *rpc.Provider, balance *felt.Felt) error {
-	stakingAddr, err := starkutils.HexToFelt(constants.StakingContract)
-	if err != nil {
+	return approveAmount(accnt, rpcProvider, balance, constants.Stakes[network])
+}
+
+// approveAmount approves the staking contract to spend amount, a u256 as its
+// low and high felts, unless the allowance already covers it.
+func approveAmount(accnt *account.Account, rpcProvider *rpc.Provider, balance *felt.Felt, amount []*felt.Felt) error {
+	approveTxn, err := approveCall(accnt, rpcProvider, amount)
+	if err != nil || approveTxn == nil {
 		return err
 	}
 
-	allowance, err := getAllowance(rpcProvider, accnt.Address, stakingAddr)
+	invokeTxn, estimatedFee, err := estimateFee(accnt, []rpc.FunctionCall{*approveTxn})
 	if err != nil {
 		return err
 	}
 
-	if starkutils.FeltToBigInt(allowance).Cmp(starkutils.FeltToBigInt(constants.Stakes[network][0])) >= 0 {
-		return nil
+	requiredAmount := new(felt.Felt).Add(amount[0], estimatedFee)
+	if balance.Cmp(requiredAmount) < 0 {
+		needed := starkutils.FRIToSTRK(requiredAmount)
+		have := starkutils.FRIToSTRK(balance)
+		return errors.New(fmt.Sprintf(utils.Red("insufficient balance to approve. Have: %.6f STRK, Need: %.6f STRK"), have, needed))
 	}
 
-	starkAddr, err := starkutils.HexToFelt(constants.StrkTokenAddress)
+	_, err = executeTxn(accnt, invokeTxn)
+	return err
+}
+
+// approveCall returns the call approving the staking contract to spend amount,
+// a u256 as its low and high felts, or nil if the allowance already covers it
+func approveCall(accnt *account.Account, rpcProvider *rpc.Provider, amount []*felt.Felt) (*rpc.FunctionCall, error) {
+	stakingAddr, err := starkutils.HexToFelt(constants.StakingContract)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	approveTxn := rpc.FunctionCall{
-		ContractAddress:    starkAddr,
-		EntryPointSelector: starkutils.GetSelectorFromNameFelt("approve"),
-		Calldata:           []*felt.Felt{stakingAddr, constants.Stakes[network][0], constants.Stakes[network][1]},
+	allowance, err := getAllowance(rpcProvider, accnt.Address, stakingAddr)
+	if err != nil {
+		return nil, err
 	}
 
-	invokeTxn, estimatedFee, err := estimateFee(accnt, []rpc.FunctionCall{approveTxn})
-	if err != nil {
-		return err
+	if starkutils.FeltToBigInt(allowance).Cmp(starkutils.FeltToBigInt(amount[0])) >= 0 {
+		return nil, nil
 	}
 
-	stakeAmount := constants.Stakes[network][0]
-	requiredAmount := new(felt.Felt).Add(stakeAmount, estimatedFee)
-	if balance.Cmp(requiredAmount) < 0 {
-		needed := starkutils.FRIToSTRK(requiredAmount)
-		have := starkutils.FRIToSTRK(balance)
-		return errors.New(fmt.Sprintf(utils.Red("insufficient balance to approve. Have: %.6f STRK, Need: %.6f STRK"), have, needed))
+	starkAddr, err := starkutils.HexToFelt(constants.StrkTokenAddress)
+	if err != nil {
+		return nil, err
 	}
 
-	return executeTxn(accnt, invokeTxn)
+	return &rpc.FunctionCall{
+		ContractAddress:    starkAddr,
+		EntryPointSelector: starkutils.GetSelectorFromNameFelt("approve"),
+		Calldata:           []*felt.Felt{stakingAddr, amount[0], amount[1]},
+	}, nil
 }
 
 // stakeAndSetCommission builds and executes the stake and set_commission transactions.
@@ -106,7 +258,10 @@ func stakeAndSetCommission(network string, accnt *account.Account, wallet types.
 		return fmt.Errorf("failed to convert stake commission to integer: %w", err)
 	}
 
-	commissionFelt := starkutils.BigIntToFelt(big.NewInt(int64(uint16(commissionInt * 100))))
+	commissionFelt, err := commissionToFelt(commissionInt)
+	if err != nil {
+		return err
+	}
 
 	setCommissionTxn := rpc.FunctionCall{
 		ContractAddress:    stackingAddr,
@@ -127,7 +282,17 @@ func stakeAndSetCommission(network string, accnt *account.Account, wallet types.
 		return errors.New(fmt.Sprintf(utils.Red("insufficient balance to stake. Have: %.6f STRK, Need: %.6f STRK"), have, needed))
 	}
 
-	return executeTxn(accnt, invokeTxn)
+	_, err = executeTxn(accnt, invokeTxn)
+	return err
+}
+
+// commissionToFelt converts a commission in percent to the basis points the
+// staking contract expects
+func commissionToFelt(percent int) (*felt.Felt, error) {
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("commission must be between 0 and 100 percent, got %d", percent)
+	}
+	return starkutils.BigIntToFelt(big.NewInt(int64(percent * 100))), nil
 }
 
 // getAllowance retrieves the allowance for a spender from an owner.
@@ -152,7 +317,7 @@ func getAllowance(rpcProvider *rpc.Provider, owner, spender *felt.Felt) (*felt.F
 
 // estimateFee estimates the fee for a set of transactions.
 func estimateFee(accnt *account.Account, calls []rpc.FunctionCall) (*rpc.BroadcastInvokeTxnV3, *felt.Felt, error) {
-	fmt.Println(utils.Cyan("Estimating transaction fees..."))
+	fmt.Fprintln(progress, utils.Cyan("Estimating transaction fees..."))
 	invokeTxn, feesEstimate, err := utils.EstimateGasFee(accnt, calls)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to estimate gas fee: %w", err)
@@ -160,7 +325,7 @@ func estimateFee(accnt *account.Account, calls []rpc.FunctionCall) (*rpc.Broadca
 
 	estimatedFee := feesEstimate[0].OverallFee
 	estimatedFeeStark := starkutils.FRIToSTRK(estimatedFee)
-	fmt.Printf(utils.Cyan("Estimated fee: %.6f STRK. Adjusting for a higher success rate...\n"), estimatedFeeStark)
+	fmt.Fprintf(progress, utils.Cyan("Estimated fee: %.6f STRK. Adjusting for a higher success rate...\n"), estimatedFeeStark)
 
 	invokeTxn.ResourceBounds = starkutils.FeeEstToResBoundsMap(feesEstimate[0], 1.5)
 
@@ -171,25 +336,25 @@ func estimateFee(accnt *account.Account, calls []rpc.FunctionCall) (*rpc.Broadca
 	return invokeTxn, estimatedFee, nil
 }
 
-// executeTxn sends a transaction and waits for its confirmation.
-func executeTxn(accnt *account.Account, invokeTxn *rpc.BroadcastInvokeTxnV3) error {
-	fmt.Println(utils.Cyan("Sending transactions..."))
+// executeTxn sends a transaction, waits for its confirmation and returns its hash.
+func executeTxn(accnt *account.Account, invokeTxn *rpc.BroadcastInvokeTxnV3) (*felt.Felt, error) {
+	fmt.Fprintln(progress, utils.Cyan("Sending transactions..."))
 	resp, err := accnt.SendTransaction(context.Background(), invokeTxn)
 	if err != nil {
-		return fmt.Errorf("failed to send transaction: %w", err)
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
 	}
 
-	fmt.Printf(utils.Green("Transaction successfully submitted! Transaction hash: %s\n"), utils.FormatTransactionHash(resp.Hash))
-	fmt.Println(utils.Cyan("Waiting for transaction confirmation..."))
+	fmt.Fprintf(progress, utils.Green("Transaction successfully submitted! Transaction hash: %s\n"), utils.FormatTransactionHash(resp.Hash))
+	fmt.Fprintln(progress, utils.Cyan("Waiting for transaction confirmation..."))
 
 	receipt, err := accnt.WaitForTransactionReceipt(context.Background(), resp.Hash, 15*time.Second)
 	if err != nil {
 		transactionURL := fmt.Sprintf("https://sepolia.voyager.online/tx/%s", utils.FormatTransactionHash(resp.Hash))
-		fmt.Printf(utils.Red("Transaction failed or timed out. View details here: %s\n"), transactionURL)
-		return fmt.Errorf("error waiting for transaction receipt: %w", err)
+		fmt.Fprintf(progress, utils.Red("Transaction failed or timed out. View details here: %s\n"), transactionURL)
+		return nil, fmt.Errorf("error waiting for transaction receipt: %w", err)
 	}
 
 	transactionURL := fmt.Sprintf("https://sepolia.voyager.online/tx/%s", utils.FormatTransactionHash(receipt.Hash))
-	fmt.Printf(utils.Green("Transaction successful! View details here: %s\n"), transactionURL)
-	return nil
+	fmt.Fprintf(progress, utils.Green("Transaction successful! View details here: %s\n"), transactionURL)
+	return receipt.Hash, nil
 }
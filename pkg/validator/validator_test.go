@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+func felts(values ...uint64) []*felt.Felt {
+	out := make([]*felt.Felt, len(values))
+	for i, v := range values {
+		out[i] = new(felt.Felt).SetUint64(v)
+	}
+	return out
+}
+
+func TestDecodeStakerInfo(t *testing.T) {
+	// Some(reward 0xa, operational 0xb, unstake_time None, amount 100, unclaimed 7, pool_info None)
+	info, err := decodeStakerInfo(felts(optionSome, 0xa, 0xb, optionNone, 100, 7, optionNone))
+	if err != nil {
+		t.Fatalf("decodeStakerInfo failed: %v", err)
+	}
+	if info.rewardAddress.Uint64() != 0xa || info.operationalAddress.Uint64() != 0xb {
+		t.Errorf("unexpected addresses %v / %v", info.rewardAddress, info.operationalAddress)
+	}
+	if !info.unstakeDeadline().IsZero() || info.amountOwn.Uint64() != 100 || info.unclaimedRewards.Uint64() != 7 {
+		t.Errorf("unexpected staker info %+v", info)
+	}
+}
+
+func TestDecodeStakerInfoWithUnstakeIntent(t *testing.T) {
+	info, err := decodeStakerInfo(felts(optionSome, 0xa, 0xb, optionSome, 1700000000, 100, 7, optionNone))
+	if err != nil {
+		t.Fatalf("decodeStakerInfo failed: %v", err)
+	}
+	if info.unstakeTime != 1700000000 || info.unstakeDeadline().Unix() != 1700000000 {
+		t.Errorf("expected the unstake time, got %d", info.unstakeTime)
+	}
+	if info.amountOwn.Uint64() != 100 || info.unclaimedRewards.Uint64() != 7 {
+		t.Errorf("expected the amounts after the unstake time, got %+v", info)
+	}
+}
+
+func TestDecodeStakerInfoNotStaker(t *testing.T) {
+	if _, err := decodeStakerInfo(felts(optionNone)); !errors.Is(err, errNotStaker) {
+		t.Errorf("expected errNotStaker, got %v", err)
+	}
+	if _, err := decodeStakerInfo(felts(optionSome, 0xa, 0xb)); err == nil || errors.Is(err, errNotStaker) {
+		t.Errorf("expected a malformed error, got %v", err)
+	}
+}
+
+func TestParseSTRK(t *testing.T) {
+	for amount, want := range map[string]string{
+		"1":       "1000000000000000000",
+		"0.5":     "500000000000000000",
+		"20000":   "20000000000000000000000",
+		"1e-18":   "1",
+		"2.00001": "2000010000000000000",
+	} {
+		got, err := ParseSTRK(amount)
+		if err != nil {
+			t.Errorf("ParseSTRK(%q) failed: %v", amount, err)
+			continue
+		}
+		if got.Text(10) != want {
+			t.Errorf("ParseSTRK(%q) = %s, want %s", amount, got.Text(10), want)
+		}
+	}
+	for _, amount := range []string{"", "0", "-1", "abc", "0.0000000000000000001"} {
+		if _, err := ParseSTRK(amount); err == nil {
+			t.Errorf("expected %q to be rejected", amount)
+		}
+	}
+}
+
+func TestCommissionToFelt(t *testing.T) {
+	got, err := commissionToFelt(12)
+	if err != nil || got.Uint64() != 1200 {
+		t.Errorf("expected 1200 basis points, got %v (%v)", got, err)
+	}
+	for _, percent := range []int{-1, 101} {
+		if _, err := commissionToFelt(percent); err == nil {
+			t.Errorf("expected %d%% to be rejected", percent)
+		}
+	}
+}
+
+func TestDecodeStakerPoolInfo(t *testing.T) {
+	// Some(commission 500), pools [(0xp1, 0xt1, 10), (0xp2, 0xt2, 20)]
+	info, err := decodeStakerPoolInfo(felts(optionSome, 500, 2, 0xa1, 0xb1, 10, 0xa2, 0xb2, 20))
+	if err != nil {
+		t.Fatalf("decodeStakerPoolInfo failed: %v", err)
+	}
+	if info.commission.Uint64() != 500 || len(info.pools) != 2 {
+		t.Fatalf("unexpected pool info %+v", info)
+	}
+	if info.pools[1].contract.Uint64() != 0xa2 || info.pools[1].token.Uint64() != 0xb2 || info.pools[1].amount.Uint64() != 20 {
+		t.Errorf("unexpected second pool %+v", info.pools[1])
+	}
+
+	info, err = decodeStakerPoolInfo(felts(optionNone, 0))
+	if err != nil || info.commission != nil || len(info.pools) != 0 {
+		t.Errorf("expected no commission and no pools, got %+v (%v)", info, err)
+	}
+	if _, err := decodeStakerPoolInfo(felts(optionNone, 1, 0xa1)); err == nil {
+		t.Error("expected a truncated pool to be rejected")
+	}
+}
+
+func TestDecodePoolMember(t *testing.T) {
+	address := new(felt.Felt).SetUint64(0xc)
+	member, ok, err := decodePoolMember(address, felts(optionSome, 0xd, 2e18, 1e17, 500, 0, optionNone))
+	if err != nil || !ok {
+		t.Fatalf("expected a member, got %v (%v)", ok, err)
+	}
+	if member.Amount != 2 || member.UnclaimedRewards != 0.1 {
+		t.Errorf("unexpected member %+v", member)
+	}
+	if _, ok, err := decodePoolMember(address, felts(optionNone)); ok || err != nil {
+		t.Errorf("expected a former member to be skipped, got %v (%v)", ok, err)
+	}
+}
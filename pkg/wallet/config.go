@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+// All returns every wallet of the config, the active one first
+func All(cfg types.StarkNodeKitConfig) []types.WalletConfig {
+	var all []types.WalletConfig
+	if hasActive(cfg) {
+		all = append(all, cfg.Wallet)
+	}
+	return append(all, cfg.Wallets...)
+}
+
+// Find returns the wallet with the given name
+func Find(cfg types.StarkNodeKitConfig, name string) (types.WalletConfig, bool) {
+	for _, w := range All(cfg) {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return types.WalletConfig{}, false
+}
+
+// IsActive reports whether a wallet is the one used for staking
+func IsActive(cfg types.StarkNodeKitConfig, name string) bool {
+	return hasActive(cfg) && cfg.Wallet.Name == name
+}
+
+// IsOperational reports whether a wallet signs for the staking validator
+func IsOperational(cfg types.StarkNodeKitConfig, w types.WalletConfig) bool {
+	return cfg.IsValidatorNode && w.Wallet.Address != "" && cfg.ValidatorConfig.SignerConfig.OperationalAddress == w.Wallet.Address
+}
+
+// Add adds a wallet to the config. It becomes the active wallet if there is none.
+func Add(cfg *types.StarkNodeKitConfig, w types.WalletConfig) error {
+	if w.Name == "" {
+		return fmt.Errorf("the wallet needs a name")
+	}
+	if _, ok := Find(*cfg, w.Name); ok {
+		return fmt.Errorf("a wallet named %s already exists", w.Name)
+	}
+	if !hasActive(*cfg) {
+		cfg.Wallet = w
+		return nil
+	}
+	cfg.Wallets = append(cfg.Wallets, w)
+	return nil
+}
+
+// Use makes a wallet the active one, the previous one stays available
+func Use(cfg *types.StarkNodeKitConfig, name string) error {
+	if IsActive(*cfg, name) {
+		return nil
+	}
+	i := slices.IndexFunc(cfg.Wallets, func(w types.WalletConfig) bool { return w.Name == name })
+	if i < 0 {
+		return fmt.Errorf("no wallet named %s", name)
+	}
+	next := cfg.Wallets[i]
+	cfg.Wallets = slices.Delete(cfg.Wallets, i, i+1)
+	if hasActive(*cfg) {
+		cfg.Wallets = append(cfg.Wallets, cfg.Wallet)
+	}
+	cfg.Wallet = next
+	return nil
+}
+
+// UseOperational makes a wallet the signer of the staking validator
+func UseOperational(cfg *types.StarkNodeKitConfig, name string) error {
+	w, ok := Find(*cfg, name)
+	if !ok {
+		return fmt.Errorf("no wallet named %s", name)
+	}
+	if w.Wallet.Keystore == "" {
+		return fmt.Errorf("wallet %s has no keystore, run `starknode-kit wallet migrate` first", name)
+	}
+	cfg.ValidatorConfig.SignerConfig.OperationalAddress = w.Wallet.Address
+	cfg.ValidatorConfig.SignerConfig.Keystore = w.Wallet.Keystore
+	cfg.ValidatorConfig.SignerConfig.WalletPrivateKey = ""
+	return nil
+}
+
+// Remove removes a wallet from the config. The active wallet and the
+// operational signer can't be removed.
+func Remove(cfg *types.StarkNodeKitConfig, name string) (types.WalletConfig, error) {
+	w, ok := Find(*cfg, name)
+	if !ok {
+		return w, fmt.Errorf("no wallet named %s", name)
+	}
+	if IsActive(*cfg, name) {
+		return w, fmt.Errorf("%s is the active wallet, switch to another one with `starknode-kit wallet use` first", name)
+	}
+	if IsOperational(*cfg, w) {
+		return w, fmt.Errorf("%s signs for the staking validator, pick another one with `starknode-kit wallet use --operational` first", name)
+	}
+	cfg.Wallets = slices.DeleteFunc(cfg.Wallets, func(w types.WalletConfig) bool { return w.Name == name })
+	return w, nil
+}
+
+func hasActive(cfg types.StarkNodeKitConfig) bool {
+	return cfg.Wallet.Name != "" || cfg.Wallet.Wallet.Address != ""
+}
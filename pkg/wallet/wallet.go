@@ -0,0 +1,142 @@
+// Package wallet manages the named Starknet wallets of the config and checks
+// imported accounts against the chain
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	starkutils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/thebuidl-grid/starknode-kit/pkg/constants"
+	"github.com/thebuidl-grid/starknode-kit/pkg/utils"
+)
+
+// Account contract families that can be imported
+const (
+	KindOpenZeppelin = "openzeppelin"
+	KindArgent       = "argent"
+	KindBraavos      = "braavos"
+)
+
+// Kinds lists the supported account contract families
+var Kinds = []string{KindOpenZeppelin, KindArgent, KindBraavos}
+
+// publicKeyGetters is the entry point returning the signer's public key of
+// each account family
+var publicKeyGetters = map[string]string{
+	KindOpenZeppelin: "get_public_key",
+	KindBraavos:      "get_public_key",
+	KindArgent:       "get_owner",
+}
+
+// classHashes are the known account classes of each family. Older accounts
+// are deployed behind a proxy class, Braavos accounts are deployed with their
+// base class and upgrade themselves in the constructor.
+var classHashes = map[string][]string{
+	KindOpenZeppelin: {
+		constants.PredeployedClassHash,                                       // v0.8.1, deployed by starknode-kit
+		"0x058d97f7d76e78f44905cc30cb65b91ea49a4b908a76703c54197bca90f81773", // v0.5.0, Cairo 0
+	},
+	KindArgent: {
+		"0x036078334509b514626504edc9fb252328d1a240e4e948bef8d0c08dff45927f", // v0.4.0
+		"0x029927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b", // v0.3.1
+		"0x01a736d6ed154502257f02b1ccdf4d9d1089f80811cd6acad48e6b6a9d1f2003", // v0.3.0
+		"0x025ec026985a3bf9d0cc1fe17326b245dfdc3ff89b8fde106542a3ea56c5a918", // Cairo 0 proxy
+	},
+	KindBraavos: {
+		"0x00816dd0297efc55dc1e7559020a3a825e81ef734b558f03c83325d4da7e6253", // v1.0.0
+		"0x013bfe114fb1cf405bfc3a7f8dbe2d91db146c17521d40dcf57e16d6b59fa8e6", // base account
+		"0x03131fa018d520a037686ce3efddeab8f28895662f019ca3ca18a626650f7d1e", // Cairo 0 proxy
+	},
+}
+
+// KindOf returns the account family of a class hash, false if the class is unknown
+func KindOf(classHash *felt.Felt) (string, bool) {
+	for _, kind := range Kinds {
+		for _, known := range classHashes[kind] {
+			if hash, err := starkutils.HexToFelt(known); err == nil && hash.Equal(classHash) {
+				return kind, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Account is an account contract as deployed on chain
+type Account struct {
+	Kind      string
+	ClassHash string
+	PublicKey string
+}
+
+// PublicKey derives the Stark public key of a private key
+func PublicKey(privateKey string) (string, error) {
+	priv, ok := new(big.Int).SetString(privateKey, 0)
+	if !ok || priv.Sign() <= 0 {
+		return "", fmt.Errorf("invalid private key")
+	}
+	x, _ := curve.PrivateKeyToPoint(priv)
+	return utils.FormatStarknetAddress(starkutils.BigIntToFelt(x)), nil
+}
+
+// Verify checks that a known account contract is deployed at address and that
+// its signer is publicKey. The account type is detected from the class hash;
+// a non-empty kind must match it.
+func Verify(ctx context.Context, provider *rpc.Provider, address, publicKey, kind string) (Account, error) {
+	if kind != "" && !slices.Contains(Kinds, kind) {
+		return Account{}, fmt.Errorf("unknown account type %q, use one of %v", kind, Kinds)
+	}
+	addr, err := starkutils.HexToFelt(address)
+	if err != nil {
+		return Account{}, fmt.Errorf("invalid address %s: %w", address, err)
+	}
+	classHash, err := provider.ClassHashAt(ctx, rpc.BlockID{Tag: "latest"}, addr)
+	if err != nil {
+		return Account{}, fmt.Errorf("no account deployed at %s: %w", address, err)
+	}
+	account := Account{ClassHash: utils.FormatStarknetAddress(classHash)}
+
+	detected, ok := KindOf(classHash)
+	if !ok {
+		return account, fmt.Errorf("%s has class %s, which is not a known %v account", address, account.ClassHash, Kinds)
+	}
+	if kind != "" && kind != detected {
+		return account, fmt.Errorf("%s is a %s account, not %s", address, detected, kind)
+	}
+	account.Kind = detected
+
+	signer, err := callGetter(ctx, provider, addr, publicKeyGetters[detected])
+	if err != nil {
+		return account, fmt.Errorf("could not read the public key of %s: %w", address, err)
+	}
+	account.PublicKey = utils.FormatStarknetAddress(signer)
+
+	want, err := starkutils.HexToFelt(publicKey)
+	if err != nil {
+		return account, err
+	}
+	if account.PublicKey != utils.FormatStarknetAddress(want) {
+		return account, fmt.Errorf("the private key does not belong to %s: its signer is %s, the key's public key is %s", address, account.PublicKey, utils.FormatStarknetAddress(want))
+	}
+	return account, nil
+}
+
+func callGetter(ctx context.Context, provider *rpc.Provider, address *felt.Felt, entryPoint string) (*felt.Felt, error) {
+	result, err := provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: starkutils.GetSelectorFromNameFelt(entryPoint),
+		Calldata:           []*felt.Felt{},
+	}, rpc.BlockID{Tag: "latest"})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%s returned nothing", entryPoint)
+	}
+	return result[0], nil
+}
@@ -0,0 +1,121 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	starkutils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/thebuidl-grid/starknode-kit/pkg/types"
+)
+
+func named(name, address string) types.WalletConfig {
+	return types.WalletConfig{Name: name, Wallet: types.Wallet{Address: address, Keystore: "/keystore/" + name + ".json"}}
+}
+
+func TestAddMakesFirstWalletActive(t *testing.T) {
+	var cfg types.StarkNodeKitConfig
+	if err := Add(&cfg, named("staking", "0x1")); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := Add(&cfg, named("ops", "0x2")); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if !IsActive(cfg, "staking") || len(cfg.Wallets) != 1 || cfg.Wallets[0].Name != "ops" {
+		t.Fatalf("unexpected wallets %+v / %+v", cfg.Wallet, cfg.Wallets)
+	}
+	if err := Add(&cfg, named("ops", "0x3")); err == nil {
+		t.Error("expected a duplicate name to be rejected")
+	}
+	if got := All(cfg); len(got) != 2 || got[0].Name != "staking" {
+		t.Errorf("expected the active wallet first, got %+v", got)
+	}
+}
+
+func TestUseSwapsActiveWallet(t *testing.T) {
+	cfg := types.StarkNodeKitConfig{Wallet: named("staking", "0x1"), Wallets: []types.WalletConfig{named("ops", "0x2")}}
+
+	if err := Use(&cfg, "ops"); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if !IsActive(cfg, "ops") {
+		t.Errorf("expected ops to be active, got %s", cfg.Wallet.Name)
+	}
+	if _, ok := Find(cfg, "staking"); !ok || len(cfg.Wallets) != 1 {
+		t.Errorf("expected the previous wallet to be kept, got %+v", cfg.Wallets)
+	}
+	if err := Use(&cfg, "missing"); err == nil {
+		t.Error("expected an unknown wallet to be rejected")
+	}
+}
+
+func TestUseOperationalAndRemove(t *testing.T) {
+	cfg := types.StarkNodeKitConfig{
+		IsValidatorNode: true,
+		Wallet:          named("staking", "0x1"),
+		Wallets:         []types.WalletConfig{named("ops", "0x2"), named("old", "0x3")},
+	}
+	cfg.ValidatorConfig.SignerConfig.WalletPrivateKey = "0xdead"
+
+	if err := UseOperational(&cfg, "ops"); err != nil {
+		t.Fatalf("UseOperational failed: %v", err)
+	}
+	signer := cfg.ValidatorConfig.SignerConfig
+	if signer.OperationalAddress != "0x2" || signer.Keystore != "/keystore/ops.json" || signer.WalletPrivateKey != "" {
+		t.Errorf("unexpected signer %+v", signer)
+	}
+
+	if _, err := Remove(&cfg, "staking"); err == nil {
+		t.Error("expected the active wallet to be kept")
+	}
+	if _, err := Remove(&cfg, "ops"); err == nil {
+		t.Error("expected the operational wallet to be kept")
+	}
+	removed, err := Remove(&cfg, "old")
+	if err != nil || removed.Wallet.Address != "0x3" {
+		t.Fatalf("expected old to be removed, got %+v (%v)", removed, err)
+	}
+	if _, ok := Find(cfg, "old"); ok {
+		t.Error("expected old to be gone")
+	}
+}
+
+func TestUseOperationalNeedsKeystore(t *testing.T) {
+	cfg := types.StarkNodeKitConfig{Wallet: types.WalletConfig{Name: "plain", Wallet: types.Wallet{Address: "0x1"}}}
+	if err := UseOperational(&cfg, "plain"); err == nil {
+		t.Error("expected a wallet without keystore to be rejected")
+	}
+}
+
+func TestPublicKey(t *testing.T) {
+	// The generator point is the public key of the private key 1
+	got, err := PublicKey("0x1")
+	if err != nil {
+		t.Fatalf("PublicKey failed: %v", err)
+	}
+	want := "0x01ef15c18599971b7beced415a40f0c7deacfd9b0d1819e03d723d8bc943cfca"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	for _, key := range []string{"", "0x0", "not a key"} {
+		if _, err := PublicKey(key); err == nil {
+			t.Errorf("expected %q to be rejected", key)
+		}
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	cases := map[string]string{
+		"0x61dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f": KindOpenZeppelin,
+		"0x29927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b": KindArgent,
+		"0x0816dd0297efc55dc1e7559020a3a825e81ef734b558f03c83325d4da7e6253": KindBraavos,
+	}
+	for hash, want := range cases {
+		classHash, _ := starkutils.HexToFelt(hash)
+		if got, ok := KindOf(classHash); !ok || got != want {
+			t.Errorf("KindOf(%s) = %q, %v, want %q", hash, got, ok, want)
+		}
+	}
+	if kind, ok := KindOf(new(felt.Felt).SetUint64(1)); ok {
+		t.Errorf("expected an unknown class to be rejected, got %q", kind)
+	}
+}